@@ -9,24 +9,54 @@ import (
 	"syscall"
 	"time"
 	"trustwallet/internal/clients/ethereum"
-	"trustwallet/internal/model"
 	ethereumParser "trustwallet/internal/parser/ethereum"
-	"trustwallet/internal/storage/inmem"
+	"trustwallet/internal/storage/bolt"
 )
 
 func main() {
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 
-	inmemStorage := inmem.New()
+	// Subscriptions, their activity and the checkpoint survive restarts.
+	boltStorage, err := bolt.Open("parser.db")
+	if err != nil {
+		log.Fatalln("Failed to open storage:", err)
+	}
+	defer boltStorage.Close()
 
 	httpClient := &http.Client{
 		Timeout: time.Second * 30,
 	}
 
-	ethereumClient := ethereum.New("https://ethereum-rpc.publicnode.com", httpClient)
+	// A single retry per endpoint; beyond that the pool fails over to the next one.
+	retryPolicy := ethereum.DefaultRetryPolicy
+	retryPolicy.MaxAttempts = 2
+
+	var endpoints []*ethereum.Client
+	for _, url := range []string{"https://ethereum-rpc.publicnode.com", "https://eth.drpc.org"} {
+		// Public nodes throttle aggressively; block fetches during catch-up get their own, smaller budget.
+		limiter := ethereum.NewRateLimiter(ethereum.RateLimit{RequestsPerSecond: 10, Burst: 20}, map[string]ethereum.RateLimit{
+			"eth_getBlockByNumber": {RequestsPerSecond: 5, Burst: 10},
+		})
+
+		endpoints = append(endpoints, ethereum.New(url, httpClient, ethereum.WithRetryPolicy(retryPolicy), ethereum.WithRateLimiter(limiter)))
+	}
+
+	ethereumClient := ethereum.NewPool(endpoints, ethereum.WithStrategy(ethereum.StrategyLowestLatency))
 
-	parser := ethereumParser.New(0, ethereumClient, inmemStorage)
+	headSubscriber := ethereum.NewHeadSubscriber("wss://ethereum-rpc.publicnode.com", nil, time.Second)
+
+	// New heads drive the parser; polling is the fallback while the socket is down.
+	// The storage keeps the checkpoint too, so it is committed with each block.
+	parser := ethereumParser.New(0, ethereumClient, boltStorage,
+		ethereumParser.WithCheckpointStore(boltStorage),
+		ethereumParser.WithReceipts(),
+		ethereumParser.WithTokenTransfers(),
+		ethereumParser.WithNFTTransfers(),
+		ethereumParser.WithBloomFilter(),
+		ethereumParser.WithHeads(headSubscriber.Subscribe(ctx)),
+		ethereumParser.WithPollInterval(12*time.Second),
+	)
 
 	wg := sync.WaitGroup{}
 
@@ -35,7 +65,16 @@ func main() {
 		log.Println("Parser started")
 		defer wg.Done()
 
-		ticker := time.NewTicker(1 * time.Second)
+		if err := parser.Run(ctx); err != nil {
+			log.Println("Parser stopped:", err)
+		}
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		ticker := time.NewTicker(10 * time.Second)
 		defer ticker.Stop()
 
 		for {
@@ -43,10 +82,7 @@ func main() {
 			case <-ctx.Done():
 				return
 			case <-ticker.C:
-				if err := parser.StartParsing(); err != nil {
-					log.Println("error parsing: ", err)
-					return
-				}
+				ethereumClient.CheckHealth(ctx)
 			}
 		}
 	}()
@@ -56,8 +92,11 @@ func main() {
 		log.Println("Print Subscribed Transactions")
 		defer wg.Done()
 
-		var address model.Address = "0xYourEthereumAddress"
-		parser.Subscribe(address)
+		address := "0xde0B295669a9FD93d5F28D9Ec85E40f4cb697BAe"
+		if err := parser.Subscribe(address); err != nil {
+			log.Println("Failed to subscribe:", err)
+			return
+		}
 
 		ticker := time.NewTicker(5 * time.Second)
 		defer ticker.Stop()
@@ -71,6 +110,14 @@ func main() {
 				for _, transaction := range transactions {
 					log.Println(transaction.BlockNumber)
 				}
+
+				for _, transfer := range parser.GetTokenTransfers(address) {
+					log.Println(transfer.BlockNumber, transfer.Token, transfer.Amount)
+				}
+
+				for _, transfer := range parser.GetNFTTransfers(address) {
+					log.Println(transfer.BlockNumber, transfer.Standard, transfer.Token, transfer.TokenID)
+				}
 			}
 		}
 	}()
@@ -4,7 +4,10 @@ go 1.22
 
 require (
 	github.com/golang/mock v1.6.0
+	github.com/gorilla/websocket v1.5.3
+	go.etcd.io/bbolt v1.3.11
 	go.uber.org/mock v0.4.0
+	golang.org/x/crypto v0.9.0
 )
 
 require (
@@ -12,5 +15,6 @@ require (
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/stretchr/objx v0.5.2 // indirect
 	github.com/stretchr/testify v1.9.0
+	golang.org/x/sys v0.10.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -0,0 +1,134 @@
+package ethereum
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"trustwallet/internal/model"
+)
+
+var ErrMissingResponse = errors.New("no response for batched request")
+
+type BatchRequest struct {
+	Method string
+	Params interface{}
+}
+
+// BatchResult is the outcome of one request of a batch. Err is set per item,
+// so one failing request does not fail the others.
+type BatchResult struct {
+	Result json.RawMessage
+	Err    error
+}
+
+// CallBatch sends all requests in a single HTTP round trip and returns their
+// results in request order. Responses are matched to requests by id, so nodes
+// may answer in any order.
+func (c *Client) CallBatch(ctx context.Context, requests []BatchRequest) ([]BatchResult, error) {
+	if len(requests) == 0 {
+		return nil, nil
+	}
+
+	rpcReqs := make([]Request, len(requests))
+	positions := make(map[int]int, len(requests))
+	for i, request := range requests {
+		rpcReqs[i] = c.newRequest(request.Method, request.Params)
+		positions[rpcReqs[i].ID] = i
+	}
+
+	var rpcResps []Response
+	err := c.retry(ctx, func() error {
+		for _, request := range requests {
+			if err := c.wait(ctx, request.Method); err != nil {
+				return err
+			}
+		}
+
+		respBytes, err := c.post(ctx, rpcReqs)
+		if err != nil {
+			return err
+		}
+
+		if err := json.Unmarshal(respBytes, &rpcResps); err != nil {
+			// Nodes that reject the whole batch answer with a single error object.
+			var rpcResp Response
+			if json.Unmarshal(respBytes, &rpcResp) == nil && rpcResp.Error != nil {
+				return rpcError(rpcResp.Error)
+			}
+
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]BatchResult, len(requests))
+	answered := make([]bool, len(requests))
+	for _, rpcResp := range rpcResps {
+		i, ok := positions[rpcResp.ID]
+		if !ok {
+			continue
+		}
+
+		answered[i] = true
+		if rpcResp.Error != nil {
+			results[i].Err = rpcError(rpcResp.Error)
+			continue
+		}
+		results[i].Result = rpcResp.Result
+	}
+
+	for i := range results {
+		if !answered[i] {
+			results[i].Err = fmt.Errorf("%w: %s", ErrMissingResponse, requests[i].Method)
+		}
+	}
+
+	return results, nil
+}
+
+// GetBlocksByNumberRange fetches the blocks in [fromBlock, toBlock] with one batched call.
+func (c *Client) GetBlocksByNumberRange(ctx context.Context, fromBlock, toBlock uint64) ([]model.Block, error) {
+	if toBlock < fromBlock {
+		return nil, nil
+	}
+
+	requests := make([]BatchRequest, 0, toBlock-fromBlock+1)
+	for number := fromBlock; number <= toBlock; number++ {
+		requests = append(requests, BatchRequest{
+			Method: "eth_getBlockByNumber",
+			Params: []interface{}{toHex(number), true},
+		})
+	}
+
+	results, err := c.CallBatch(ctx, requests)
+	if err != nil {
+		return nil, err
+	}
+
+	blocks := make([]model.Block, len(results))
+	for i, result := range results {
+		if result.Err != nil {
+			return nil, fmt.Errorf("block %d: %w", fromBlock+uint64(i), result.Err)
+		}
+
+		if isNull(result.Result) {
+			return nil, fmt.Errorf("block %d: %w", fromBlock+uint64(i), ErrBlockNotFound)
+		}
+
+		var blockResp Block
+		if err := json.Unmarshal(result.Result, &blockResp); err != nil {
+			return nil, fmt.Errorf("block %d: %w", fromBlock+uint64(i), err)
+		}
+
+		if blocks[i], err = blockResp.toModel(); err != nil {
+			return nil, fmt.Errorf("block %d: %w", fromBlock+uint64(i), err)
+		}
+	}
+
+	return blocks, nil
+}
@@ -2,35 +2,76 @@ package ethereum
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"math/big"
 	"net/http"
 	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
 	"trustwallet/internal/model"
 )
 
-var ErrRPC = errors.New("RPC Error")
+var (
+	ErrRPC = errors.New("RPC Error")
+	// ErrBlockNotFound is returned for a block the node does not have yet.
+	ErrBlockNotFound = errors.New("block not found")
+)
 
 type Client struct {
-	url    string
-	client *http.Client
+	url         string
+	client      *http.Client
+	requestID   *atomic.Int64
+	retryPolicy RetryPolicy
+	limiter     *RateLimiter
 }
 
-func New(url string, httpClient *http.Client) *Client {
+type Option func(*Client)
+
+// WithRetryPolicy makes the client retry failed requests according to policy.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retryPolicy = policy
+	}
+}
+
+// WithRateLimiter keeps the client's requests within the limiter's budget.
+// A limiter may be shared by clients talking to the same provider.
+func WithRateLimiter(limiter *RateLimiter) Option {
+	return func(c *Client) {
+		c.limiter = limiter
+	}
+}
+
+func New(url string, httpClient *http.Client, opts ...Option) *Client {
 	if httpClient == nil {
 		httpClient = http.DefaultClient
 	}
 
-	return &Client{
-		url:    url,
-		client: httpClient,
+	c := &Client{
+		url:         url,
+		client:      httpClient,
+		requestID:   &atomic.Int64{},
+		retryPolicy: noRetry,
+	}
+
+	for _, opt := range opts {
+		opt(c)
 	}
+
+	return c
+}
+
+func (c *Client) URL() string {
+	return c.url
 }
 
-func (c *Client) GetLatestBlockNumber() (int64, error) {
-	rawJson, err := c.call("eth_blockNumber", []interface{}{})
+func (c *Client) GetLatestBlockNumber(ctx context.Context) (uint64, error) {
+	rawJson, err := c.call(ctx, "eth_blockNumber", []interface{}{})
 	if err != nil {
 		return 0, err
 	}
@@ -40,44 +81,170 @@ func (c *Client) GetLatestBlockNumber() (int64, error) {
 		return 0, err
 	}
 
-	blockNumber, err := strconv.ParseInt(blockHex[2:], 16, 64)
+	return parseHexUint64(blockHex)
+}
+
+func (c *Client) GetBlockNumberByTag(ctx context.Context, tag model.BlockTag) (uint64, error) {
+	rawJson, err := c.call(ctx, "eth_getBlockByNumber", []interface{}{tag, false})
 	if err != nil {
 		return 0, err
 	}
 
-	return blockNumber, nil
+	if isNull(rawJson) {
+		return 0, fmt.Errorf("%s block: %w", tag, ErrBlockNotFound)
+	}
+
+	var header BlockHeader
+	if err := json.Unmarshal(rawJson, &header); err != nil {
+		return 0, err
+	}
+
+	return parseHexUint64(header.Number)
 }
 
-func (c *Client) GetTransactionsByBlockNumber(blockNumber int64) ([]model.Transaction, error) {
-	blockHex := "0x" + strconv.FormatInt(blockNumber, 16)
+func (c *Client) GetBlockByNumber(ctx context.Context, blockNumber uint64) (model.Block, error) {
+	blockResp, err := c.getBlock(ctx, blockNumber)
+	if err != nil {
+		return model.Block{}, err
+	}
+
+	return blockResp.toModel()
+}
 
-	rawJson, err := c.call("eth_getBlockByNumber", []interface{}{blockHex, true})
+func (c *Client) GetTransactionsByBlockNumber(ctx context.Context, blockNumber uint64) ([]model.Transaction, error) {
+	blockResp, err := c.getBlock(ctx, blockNumber)
 	if err != nil {
 		return nil, err
 	}
 
+	block, err := blockResp.toModel()
+	if err != nil {
+		return nil, err
+	}
+
+	return block.Transactions, nil
+}
+
+// GetBlockReceipts fetches the receipts of every transaction in a block.
+func (c *Client) GetBlockReceipts(ctx context.Context, blockNumber uint64) ([]model.Receipt, error) {
+	rawJson, err := c.call(ctx, "eth_getBlockReceipts", []interface{}{toHex(blockNumber)})
+	if err != nil {
+		return nil, err
+	}
+
+	var receiptsResp []Receipt
+	if err := json.Unmarshal(rawJson, &receiptsResp); err != nil {
+		return nil, err
+	}
+
+	receipts := make([]model.Receipt, len(receiptsResp))
+	for i, receipt := range receiptsResp {
+		if receipts[i], err = receipt.toModel(); err != nil {
+			return nil, fmt.Errorf("receipt %s: %w", receipt.TransactionHash, err)
+		}
+	}
+
+	return receipts, nil
+}
+
+// GetLogs fetches the logs a block emitted whose first topic, the event
+// signature, is one of topics.
+func (c *Client) GetLogs(ctx context.Context, blockNumber uint64, topics []string) ([]model.Log, error) {
+	filter := LogFilter{
+		FromBlock: toHex(blockNumber),
+		ToBlock:   toHex(blockNumber),
+		Topics:    [][]string{topics},
+	}
+
+	rawJson, err := c.call(ctx, "eth_getLogs", []interface{}{filter})
+	if err != nil {
+		return nil, err
+	}
+
+	var logsResp []Log
+	if err := json.Unmarshal(rawJson, &logsResp); err != nil {
+		return nil, err
+	}
+
+	logs := make([]model.Log, len(logsResp))
+	for i, log := range logsResp {
+		if logs[i], err = log.toModel(); err != nil {
+			return nil, fmt.Errorf("log %s: %w", log.LogIndex, err)
+		}
+	}
+
+	return logs, nil
+}
+
+func (c *Client) getBlock(ctx context.Context, blockNumber uint64) (Block, error) {
+	rawJson, err := c.call(ctx, "eth_getBlockByNumber", []interface{}{toHex(blockNumber), true})
+	if err != nil {
+		return Block{}, err
+	}
+
+	if isNull(rawJson) {
+		return Block{}, fmt.Errorf("block %d: %w", blockNumber, ErrBlockNotFound)
+	}
+
 	var blockResp Block
 	if err := json.Unmarshal(rawJson, &blockResp); err != nil {
-		return nil, err
+		return Block{}, err
 	}
 
-	return blockResp.Transactions, nil
+	return blockResp, nil
+}
+
+// isNull reports whether a JSON-RPC result is null, which nodes return for
+// blocks they have not seen.
+func isNull(raw json.RawMessage) bool {
+	return len(raw) == 0 || string(bytes.TrimSpace(raw)) == "null"
+}
+
+func (c *Client) call(ctx context.Context, method string, params interface{}) (json.RawMessage, error) {
+	var result json.RawMessage
+	err := c.retry(ctx, func() error {
+		if err := c.wait(ctx, method); err != nil {
+			return err
+		}
+
+		respBytes, err := c.post(ctx, c.newRequest(method, params))
+		if err != nil {
+			return err
+		}
+
+		var rpcResp Response
+		err = json.Unmarshal(respBytes, &rpcResp)
+		if err != nil {
+			return err
+		}
+
+		if rpcResp.Error != nil {
+			return rpcError(rpcResp.Error)
+		}
+
+		result = rpcResp.Result
+		return nil
+	})
+
+	return result, err
 }
 
-func (c *Client) call(method string, params interface{}) (json.RawMessage, error) {
-	rpcReq := map[string]interface{}{
-		"jsonrpc": "2.0",
-		"method":  method,
-		"params":  params,
-		"id":      1,
+func (c *Client) newRequest(method string, params interface{}) Request {
+	return Request{
+		JSONRPC: "2.0",
+		ID:      int(c.requestID.Add(1)),
+		Method:  method,
+		Params:  params,
 	}
+}
 
-	reqBytes, err := json.Marshal(rpcReq)
+func (c *Client) post(ctx context.Context, body interface{}) ([]byte, error) {
+	reqBytes, err := json.Marshal(body)
 	if err != nil {
 		return nil, err
 	}
 
-	req, err := http.NewRequest("POST", c.url, bytes.NewReader(reqBytes))
+	req, err := http.NewRequestWithContext(ctx, "POST", c.url, bytes.NewReader(reqBytes))
 	if err != nil {
 		return nil, err
 	}
@@ -95,15 +262,70 @@ func (c *Client) call(method string, params interface{}) (json.RawMessage, error
 		return nil, err
 	}
 
-	var rpcResp Response
-	err = json.Unmarshal(respBytes, &rpcResp)
-	if err != nil {
-		return nil, err
+	if resp.StatusCode != http.StatusOK {
+		retryAfter := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+		if retryAfter > 0 && c.limiter != nil {
+			c.limiter.Backoff(retryAfter)
+		}
+
+		return nil, &HTTPError{StatusCode: resp.StatusCode, Body: string(bytes.TrimSpace(respBytes)), RetryAfter: retryAfter}
 	}
 
-	if rpcResp.Error != nil {
-		return nil, fmt.Errorf("%w: %d: %s", ErrRPC, rpcResp.Error.Code, rpcResp.Error.Message)
+	return respBytes, nil
+}
+
+func (c *Client) wait(ctx context.Context, method string) error {
+	if c.limiter == nil {
+		return nil
+	}
+
+	return c.limiter.Wait(ctx, method)
+}
+
+func parseHexUint64(hex string) (uint64, error) {
+	if len(hex) < 2 || hex[:2] != "0x" {
+		return 0, fmt.Errorf("invalid hex quantity %q", hex)
+	}
+
+	return strconv.ParseUint(hex[2:], 16, 64)
+}
+
+// parseOptionalHexUint64 parses a hex quantity, treating a missing one as zero.
+func parseOptionalHexUint64(hex string) (uint64, error) {
+	if hex == "" {
+		return 0, nil
+	}
+
+	if len(hex) < 2 || hex[:2] != "0x" {
+		return 0, fmt.Errorf("invalid hex quantity %q", hex)
 	}
 
-	return rpcResp.Result, nil
+	return strconv.ParseUint(hex[2:], 16, 64)
+}
+
+// parseOptionalHexBig parses a hex quantity of any size, returning nil for a missing one.
+func parseOptionalHexBig(hex string) (*big.Int, error) {
+	if hex == "" {
+		return nil, nil
+	}
+
+	value, ok := new(big.Int).SetString(strings.TrimPrefix(hex, "0x"), 16)
+	if !ok || !strings.HasPrefix(hex, "0x") {
+		return nil, fmt.Errorf("invalid hex quantity %q", hex)
+	}
+
+	return value, nil
+}
+
+func parseHexTime(hex string) (time.Time, error) {
+	seconds, err := parseOptionalHexUint64(hex)
+	if err != nil || seconds == 0 {
+		return time.Time{}, err
+	}
+
+	return time.Unix(int64(seconds), 0).UTC(), nil
+}
+
+func toHex(number uint64) string {
+	return "0x" + strconv.FormatUint(number, 16)
 }
@@ -1,12 +1,16 @@
 package ethereum_test
 
 import (
+	"context"
 	"encoding/json"
 	"github.com/stretchr/testify/assert"
 	"io"
+	"math/big"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 	"trustwallet/internal/clients/ethereum"
 	"trustwallet/internal/model"
 )
@@ -34,35 +38,182 @@ func TestClient_GetLatestBlockNumber(t *testing.T) {
 
 	client := ethereum.New(server.URL, server.Client())
 
-	blockNumber, err := client.GetLatestBlockNumber()
+	blockNumber, err := client.GetLatestBlockNumber(context.Background())
 	assert.NoError(t, err)
-	assert.Equal(t, int64(68943), blockNumber)
+	assert.Equal(t, uint64(68943), blockNumber)
 }
 
 func TestClient_GetTransactionsByBlockNumber(t *testing.T) {
-	blockNumberHex := "0x1"
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, err := w.Write([]byte(`{
+            "jsonrpc": "2.0",
+            "id": 1,
+            "result": {
+                "number": "0x1",
+                "hash": "0xBlockHash",
+                "parentHash": "0xParentHash",
+                "timestamp": "0x65f1a2b0",
+                "transactions": [{
+                    "hash": "0xTxHash1",
+                    "from": "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+                    "to": "0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359",
+                    "value": "0x38d7ea4c68000",
+                    "blockNumber": "0x1",
+                    "blockHash": "0xBlockHash",
+                    "transactionIndex": "0x3",
+                    "nonce": "0x2a",
+                    "gas": "0x5208",
+                    "gasPrice": "0x4a817c800",
+                    "maxFeePerGas": "0x6fc23ac00",
+                    "maxPriorityFeePerGas": "0x3b9aca00",
+                    "input": "0x",
+                    "type": "0x2",
+                    "chainId": "0x1"
+                }, {
+                    "hash": "0xTxHash2",
+                    "from": "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+                    "to": null,
+                    "value": "0xde0b6b3a7640000",
+                    "blockNumber": "0x1",
+                    "nonce": "0x2b",
+                    "gas": "0x30d40",
+                    "gasPrice": "0x4a817c800",
+                    "input": "0x6080",
+                    "type": "0x0"
+                }]
+            }
+        }`))
+		assert.NoError(t, err)
+	}
 
-	mockTransactions := []model.Transaction{
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	client := ethereum.New(server.URL, server.Client())
+
+	transactions, err := client.GetTransactionsByBlockNumber(context.Background(), 1)
+	assert.NoError(t, err)
+
+	timestamp := time.Unix(0x65f1a2b0, 0).UTC()
+	assert.Equal(t, []model.Transaction{
+		{
+			Hash:                 "0xTxHash1",
+			From:                 "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed",
+			To:                   "0xfb6916095ca1df60bb79ce92ce3ea74c37c5d359",
+			Value:                big.NewInt(0x38d7ea4c68000),
+			BlockNumber:          1,
+			BlockHash:            "0xBlockHash",
+			Timestamp:            timestamp,
+			TransactionIndex:     3,
+			Nonce:                42,
+			Gas:                  21000,
+			GasPrice:             big.NewInt(20_000_000_000),
+			MaxFeePerGas:         big.NewInt(30_000_000_000),
+			MaxPriorityFeePerGas: big.NewInt(1_000_000_000),
+			Input:                "0x",
+			Type:                 model.TransactionTypeDynamicFee,
+			ChainID:              big.NewInt(1),
+		},
 		{
-			Hash:        "0xTxHash1",
-			From:        "0xFromAddress",
-			To:          "0xToAddress",
-			Value:       "0x38d7ea4c68000",
-			BlockNumber: "0x1",
+			Hash:        "0xTxHash2",
+			From:        "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed",
+			Value:       big.NewInt(1_000_000_000_000_000_000),
+			BlockNumber: 1,
+			Timestamp:   timestamp,
+			Nonce:       43,
+			Gas:         200000,
+			GasPrice:    big.NewInt(20_000_000_000),
+			Input:       "0x6080",
+			Type:        model.TransactionTypeLegacy,
+
+			ContractAddress: model.CreateAddress("0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", 43),
 		},
+	}, transactions)
+}
+
+func TestClient_GetBlockReceipts(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		bodyBytes, _ := io.ReadAll(r.Body)
+		assert.Contains(t, string(bodyBytes), `"method":"eth_getBlockReceipts","params":["0x64"]`)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, err := w.Write([]byte(`{
+            "jsonrpc": "2.0",
+            "id": 1,
+            "result": [
+                {"transactionHash": "0xTxHash1", "status": "0x1", "gasUsed": "0x5208", "effectiveGasPrice": "0x4a817c800", "contractAddress": null},
+                {"transactionHash": "0xTxHash2", "status": "0x0", "gasUsed": "0x1d4c0", "effectiveGasPrice": "0x4a817c800", "contractAddress": "0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB"}
+            ]
+        }`))
+		assert.NoError(t, err)
 	}
 
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	client := ethereum.New(server.URL, server.Client())
+
+	receipts, err := client.GetBlockReceipts(context.Background(), 100)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.Receipt{
+		{TransactionHash: "0xTxHash1", Status: model.ReceiptStatusSuccess, GasUsed: 21000, EffectiveGasPrice: big.NewInt(20_000_000_000)},
+		{TransactionHash: "0xTxHash2", Status: model.ReceiptStatusFailed, GasUsed: 120000, EffectiveGasPrice: big.NewInt(20_000_000_000), ContractAddress: "0xdbf03b407c01e7cd3cbea99509d93f8dddc8c6fb"},
+	}, receipts)
+}
+
+func TestClient_GetLogs(t *testing.T) {
 	handler := func(w http.ResponseWriter, r *http.Request) {
-		blockResponse := ethereum.Block{
-			Number:       blockNumberHex,
-			Hash:         "0xBlockHash",
-			Transactions: mockTransactions,
-		}
+		bodyBytes, _ := io.ReadAll(r.Body)
+		assert.Contains(t, string(bodyBytes), `"method":"eth_getLogs","params":[{"fromBlock":"0x64","toBlock":"0x64","topics":[["0xTopic"]]}]`)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, err := w.Write([]byte(`{
+            "jsonrpc": "2.0",
+            "id": 1,
+            "result": [{
+                "address": "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+                "topics": ["0xTopic", "0xIndexed"],
+                "data": "0x01",
+                "blockNumber": "0x64",
+                "transactionHash": "0xTxHash1",
+                "logIndex": "0x7"
+            }]
+        }`))
+		assert.NoError(t, err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	client := ethereum.New(server.URL, server.Client())
+
+	logs, err := client.GetLogs(context.Background(), 100, []string{"0xTopic"})
+	assert.NoError(t, err)
+	assert.Equal(t, []model.Log{{
+		Address:         "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed",
+		Topics:          []string{"0xTopic", "0xIndexed"},
+		Data:            "0x01",
+		BlockNumber:     100,
+		TransactionHash: "0xTxHash1",
+		LogIndex:        7,
+	}}, logs)
+}
+
+func TestClient_GetBlockByNumber(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		bodyBytes, _ := io.ReadAll(r.Body)
+		assert.Contains(t, string(bodyBytes), `"params":["0x64",true]`)
 
 		response := ethereum.Response{
 			JSONRPC: "2.0",
 			ID:      1,
-			Result:  marshalJSON(t, blockResponse),
+			Result: marshalJSON(t, ethereum.Block{
+				Number:     "0x64",
+				Hash:       "0xBlockHash",
+				ParentHash: "0xParentHash",
+				LogsBloom:  "0x80" + strings.Repeat("00", model.BloomLength-1),
+			}),
 		}
 
 		w.Header().Set("Content-Type", "application/json")
@@ -75,9 +226,50 @@ func TestClient_GetTransactionsByBlockNumber(t *testing.T) {
 
 	client := ethereum.New(server.URL, server.Client())
 
-	transactions, err := client.GetTransactionsByBlockNumber(1)
+	bloom := model.Bloom{0: 0x80}
+
+	block, err := client.GetBlockByNumber(context.Background(), 100)
 	assert.NoError(t, err)
-	assert.Equal(t, mockTransactions, transactions)
+	assert.Equal(t, model.Block{Number: 100, Hash: "0xBlockHash", ParentHash: "0xParentHash", LogsBloom: &bloom}, block)
+}
+
+func TestClient_GetBlockByNumber_NotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, err := w.Write([]byte(`{"jsonrpc": "2.0", "id": 1, "result": null}`))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	client := ethereum.New(server.URL, server.Client())
+
+	_, err := client.GetBlockByNumber(context.Background(), 100)
+	assert.ErrorIs(t, err, ethereum.ErrBlockNotFound)
+	assert.False(t, ethereum.IsRetryable(err))
+}
+
+func TestClient_GetBlockNumberByTag(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		bodyBytes, _ := io.ReadAll(r.Body)
+		assert.Contains(t, string(bodyBytes), `"params":["finalized",false]`)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, err := w.Write([]byte(`{
+            "jsonrpc": "2.0",
+            "id": 1,
+            "result": {"number": "0x10d4f", "hash": "0xBlockHash", "parentHash": "0xParentHash", "transactions": ["0xTxHash1"]}
+        }`))
+		assert.NoError(t, err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	client := ethereum.New(server.URL, server.Client())
+
+	blockNumber, err := client.GetBlockNumberByTag(context.Background(), model.BlockTagFinalized)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(68943), blockNumber)
 }
 
 func marshalJSON(t *testing.T, v interface{}) []byte {
@@ -97,7 +289,7 @@ func TestClient_GetLatestBlockNumber_HTTPError(t *testing.T) {
 
 	client := ethereum.New(server.URL, server.Client())
 
-	_, err := client.GetLatestBlockNumber()
+	_, err := client.GetLatestBlockNumber(context.Background())
 	assert.Error(t, err)
 }
 
@@ -120,7 +312,7 @@ func TestClient_GetLatestBlockNumber_RPCError(t *testing.T) {
 
 	client := ethereum.New(server.URL, server.Client())
 
-	_, err := client.GetLatestBlockNumber()
+	_, err := client.GetLatestBlockNumber(context.Background())
 	assert.Error(t, err)
 	assert.ErrorIs(t, err, ethereum.ErrRPC)
 }
@@ -137,6 +329,119 @@ func TestClient_GetLatestBlockNumber_JSONUnmarshalError(t *testing.T) {
 
 	client := ethereum.New(server.URL, server.Client())
 
-	_, err := client.GetLatestBlockNumber()
+	_, err := client.GetLatestBlockNumber(context.Background())
 	assert.Error(t, err)
 }
+
+func TestClient_GetLatestBlockNumber_Cancelled(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.ReadAll(r.Body)
+		<-r.Context().Done()
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	client := ethereum.New(server.URL, server.Client(), ethereum.WithRetryPolicy(ethereum.DefaultRetryPolicy))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := client.GetLatestBlockNumber(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestClient_CallBatch(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		var requests []ethereum.Request
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&requests))
+		assert.Len(t, requests, 3)
+
+		// Answer in reverse order, fail the second request and drop the first one.
+		responses := []ethereum.Response{
+			{JSONRPC: "2.0", ID: requests[2].ID, Result: json.RawMessage(`"0x3"`)},
+			{JSONRPC: "2.0", ID: requests[1].ID, Error: &ethereum.Error{Code: -32000, Message: "header not found"}},
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, err := w.Write(marshalJSON(t, responses))
+		assert.NoError(t, err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	client := ethereum.New(server.URL, server.Client())
+
+	results, err := client.CallBatch(context.Background(), []ethereum.BatchRequest{
+		{Method: "eth_blockNumber", Params: []interface{}{}},
+		{Method: "eth_getBlockByNumber", Params: []interface{}{"0x2", true}},
+		{Method: "eth_chainId", Params: []interface{}{}},
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, results, 3)
+	assert.ErrorIs(t, results[0].Err, ethereum.ErrMissingResponse)
+	assert.ErrorIs(t, results[1].Err, ethereum.ErrRPC)
+	assert.NoError(t, results[2].Err)
+	assert.JSONEq(t, `"0x3"`, string(results[2].Result))
+}
+
+func TestClient_CallBatch_Rejected(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, err := w.Write([]byte(`{
+            "jsonrpc": "2.0",
+            "id": null,
+            "error": {
+                "code": -32600,
+                "message": "batch requests are not supported"
+            }
+        }`))
+		assert.NoError(t, err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	client := ethereum.New(server.URL, server.Client())
+
+	_, err := client.CallBatch(context.Background(), []ethereum.BatchRequest{{Method: "eth_blockNumber", Params: []interface{}{}}})
+	assert.ErrorIs(t, err, ethereum.ErrRPC)
+}
+
+func TestClient_GetBlocksByNumberRange(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		var requests []ethereum.Request
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&requests))
+
+		responses := make([]ethereum.Response, 0, len(requests))
+		for _, request := range requests {
+			assert.Equal(t, "eth_getBlockByNumber", request.Method)
+
+			number := request.Params.([]interface{})[0].(string)
+			responses = append(responses, ethereum.Response{
+				JSONRPC: "2.0",
+				ID:      request.ID,
+				Result:  marshalJSON(t, ethereum.Block{Number: number, Hash: "0xHash" + number, ParentHash: "0xParent" + number}),
+			})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, err := w.Write(marshalJSON(t, responses))
+		assert.NoError(t, err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	client := ethereum.New(server.URL, server.Client())
+
+	blocks, err := client.GetBlocksByNumberRange(context.Background(), 9, 11)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.Block{
+		{Number: 9, Hash: "0xHash0x9", ParentHash: "0xParent0x9"},
+		{Number: 10, Hash: "0xHash0xa", ParentHash: "0xParent0xa"},
+		{Number: 11, Hash: "0xHash0xb", ParentHash: "0xParent0xb"},
+	}, blocks)
+}
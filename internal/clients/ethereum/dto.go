@@ -2,13 +2,232 @@ package ethereum
 
 import (
 	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
 	"trustwallet/internal/model"
 )
 
 type Block struct {
-	Number       string              `json:"number"`
-	Hash         string              `json:"hash"`
-	Transactions []model.Transaction `json:"transactions"`
+	Number       string        `json:"number"`
+	Hash         string        `json:"hash"`
+	ParentHash   string        `json:"parentHash"`
+	Timestamp    string        `json:"timestamp,omitempty"`
+	LogsBloom    string        `json:"logsBloom,omitempty"`
+	Transactions []Transaction `json:"transactions"`
+}
+
+func (b Block) toModel() (model.Block, error) {
+	number, err := parseHexUint64(b.Number)
+	if err != nil {
+		return model.Block{}, err
+	}
+
+	timestamp, err := parseHexTime(b.Timestamp)
+	if err != nil {
+		return model.Block{}, fmt.Errorf("block timestamp: %w", err)
+	}
+
+	transactions, err := b.transactions(timestamp)
+	if err != nil {
+		return model.Block{}, err
+	}
+
+	var logsBloom *model.Bloom
+	if b.LogsBloom != "" {
+		bloom, err := model.ParseBloom(b.LogsBloom)
+		if err != nil {
+			return model.Block{}, err
+		}
+		logsBloom = &bloom
+	}
+
+	return model.Block{
+		Number:       number,
+		Hash:         b.Hash,
+		ParentHash:   b.ParentHash,
+		Timestamp:    timestamp,
+		Transactions: transactions,
+		LogsBloom:    logsBloom,
+	}, nil
+}
+
+// transactions decodes the block's transactions, stamping them with the time
+// of the block since nodes only report it on the block.
+func (b Block) transactions(timestamp time.Time) ([]model.Transaction, error) {
+	if b.Transactions == nil {
+		return nil, nil
+	}
+
+	transactions := make([]model.Transaction, len(b.Transactions))
+	for i, tx := range b.Transactions {
+		var err error
+		if transactions[i], err = tx.toModel(); err != nil {
+			return nil, fmt.Errorf("transaction %s: %w", tx.Hash, err)
+		}
+		transactions[i].Timestamp = timestamp
+	}
+
+	return transactions, nil
+}
+
+// Transaction is a transaction object as returned by eth_getBlockByNumber.
+// Quantities are hex encoded; fields that do not apply to the transaction's
+// type are omitted by the node.
+type Transaction struct {
+	Hash                 string        `json:"hash"`
+	From                 model.Address `json:"from"`
+	To                   model.Address `json:"to"`
+	Value                string        `json:"value"`
+	BlockNumber          string        `json:"blockNumber"`
+	BlockHash            string        `json:"blockHash,omitempty"`
+	TransactionIndex     string        `json:"transactionIndex,omitempty"`
+	Nonce                string        `json:"nonce,omitempty"`
+	Gas                  string        `json:"gas,omitempty"`
+	GasPrice             string        `json:"gasPrice,omitempty"`
+	MaxFeePerGas         string        `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas string        `json:"maxPriorityFeePerGas,omitempty"`
+	Input                string        `json:"input,omitempty"`
+	Type                 string        `json:"type,omitempty"`
+	ChainID              string        `json:"chainId,omitempty"`
+}
+
+func (t Transaction) toModel() (model.Transaction, error) {
+	tx := model.Transaction{
+		Hash:      t.Hash,
+		From:      normalizeAddress(t.From),
+		To:        normalizeAddress(t.To),
+		BlockHash: t.BlockHash,
+		Input:     t.Input,
+	}
+
+	var err error
+	if tx.Value, err = parseOptionalHexBig(t.Value); err != nil {
+		return model.Transaction{}, fmt.Errorf("value: %w", err)
+	}
+	if tx.BlockNumber, err = parseOptionalHexUint64(t.BlockNumber); err != nil {
+		return model.Transaction{}, fmt.Errorf("blockNumber: %w", err)
+	}
+	if tx.TransactionIndex, err = parseOptionalHexUint64(t.TransactionIndex); err != nil {
+		return model.Transaction{}, fmt.Errorf("transactionIndex: %w", err)
+	}
+	if tx.Nonce, err = parseOptionalHexUint64(t.Nonce); err != nil {
+		return model.Transaction{}, fmt.Errorf("nonce: %w", err)
+	}
+	if tx.Gas, err = parseOptionalHexUint64(t.Gas); err != nil {
+		return model.Transaction{}, fmt.Errorf("gas: %w", err)
+	}
+	if tx.GasPrice, err = parseOptionalHexBig(t.GasPrice); err != nil {
+		return model.Transaction{}, fmt.Errorf("gasPrice: %w", err)
+	}
+	if tx.MaxFeePerGas, err = parseOptionalHexBig(t.MaxFeePerGas); err != nil {
+		return model.Transaction{}, fmt.Errorf("maxFeePerGas: %w", err)
+	}
+	if tx.MaxPriorityFeePerGas, err = parseOptionalHexBig(t.MaxPriorityFeePerGas); err != nil {
+		return model.Transaction{}, fmt.Errorf("maxPriorityFeePerGas: %w", err)
+	}
+	if tx.ChainID, err = parseOptionalHexBig(t.ChainID); err != nil {
+		return model.Transaction{}, fmt.Errorf("chainId: %w", err)
+	}
+
+	txType, err := parseOptionalHexUint64(t.Type)
+	if err != nil || txType > math.MaxUint8 {
+		return model.Transaction{}, fmt.Errorf("invalid transaction type %q", t.Type)
+	}
+	tx.Type = uint8(txType)
+
+	// The receipt names the deployed contract too, but is not always fetched.
+	if tx.IsContractCreation() {
+		tx.ContractAddress = model.CreateAddress(tx.From, tx.Nonce)
+	}
+
+	return tx, nil
+}
+
+// Receipt is a receipt object as returned by eth_getBlockReceipts.
+type Receipt struct {
+	TransactionHash   string        `json:"transactionHash"`
+	Status            string        `json:"status"`
+	GasUsed           string        `json:"gasUsed"`
+	EffectiveGasPrice string        `json:"effectiveGasPrice,omitempty"`
+	ContractAddress   model.Address `json:"contractAddress,omitempty"`
+}
+
+func (r Receipt) toModel() (model.Receipt, error) {
+	status, err := parseOptionalHexUint64(r.Status)
+	if err != nil || status > uint64(model.ReceiptStatusSuccess) {
+		return model.Receipt{}, fmt.Errorf("invalid receipt status %q", r.Status)
+	}
+
+	gasUsed, err := parseOptionalHexUint64(r.GasUsed)
+	if err != nil {
+		return model.Receipt{}, fmt.Errorf("gasUsed: %w", err)
+	}
+
+	effectiveGasPrice, err := parseOptionalHexBig(r.EffectiveGasPrice)
+	if err != nil {
+		return model.Receipt{}, fmt.Errorf("effectiveGasPrice: %w", err)
+	}
+
+	return model.Receipt{
+		TransactionHash:   r.TransactionHash,
+		Status:            model.ReceiptStatus(status),
+		GasUsed:           gasUsed,
+		EffectiveGasPrice: effectiveGasPrice,
+		ContractAddress:   normalizeAddress(r.ContractAddress),
+	}, nil
+}
+
+// Log is a log object as returned by eth_getLogs.
+type Log struct {
+	Address         model.Address `json:"address"`
+	Topics          []string      `json:"topics"`
+	Data            string        `json:"data"`
+	BlockNumber     string        `json:"blockNumber"`
+	TransactionHash string        `json:"transactionHash"`
+	LogIndex        string        `json:"logIndex"`
+}
+
+func (l Log) toModel() (model.Log, error) {
+	blockNumber, err := parseOptionalHexUint64(l.BlockNumber)
+	if err != nil {
+		return model.Log{}, fmt.Errorf("blockNumber: %w", err)
+	}
+
+	logIndex, err := parseOptionalHexUint64(l.LogIndex)
+	if err != nil {
+		return model.Log{}, fmt.Errorf("logIndex: %w", err)
+	}
+
+	return model.Log{
+		Address:         normalizeAddress(l.Address),
+		Topics:          l.Topics,
+		Data:            l.Data,
+		BlockNumber:     blockNumber,
+		TransactionHash: l.TransactionHash,
+		LogIndex:        logIndex,
+	}, nil
+}
+
+// LogFilter is the filter object of eth_getLogs. Topics[0] lists the event
+// signatures to match; an empty filter position matches anything.
+type LogFilter struct {
+	FromBlock string     `json:"fromBlock"`
+	ToBlock   string     `json:"toBlock"`
+	Topics    [][]string `json:"topics,omitempty"`
+}
+
+// normalizeAddress puts an address returned by a node in canonical form. Nodes
+// return lowercase addresses, but nothing in the spec requires them to.
+func normalizeAddress(address model.Address) model.Address {
+	return model.Address(strings.ToLower(string(address)))
+}
+
+type BlockHeader struct {
+	Number     string `json:"number"`
+	Hash       string `json:"hash"`
+	ParentHash string `json:"parentHash"`
 }
 
 type Error struct {
@@ -16,9 +235,25 @@ type Error struct {
 	Message string `json:"message"`
 }
 
+type Request struct {
+	JSONRPC string      `json:"jsonrpc"`
+	ID      int         `json:"id"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params"`
+}
+
 type Response struct {
 	JSONRPC string          `json:"jsonrpc"`
 	ID      int             `json:"id"`
 	Result  json.RawMessage `json:"result"`
 	Error   *Error          `json:"error,omitempty"`
 }
+
+type Notification struct {
+	JSONRPC string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  struct {
+		Subscription string          `json:"subscription"`
+		Result       json.RawMessage `json:"result"`
+	} `json:"params"`
+}
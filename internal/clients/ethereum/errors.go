@@ -0,0 +1,102 @@
+package ethereum
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RPCError is a JSON-RPC error returned by the node. It matches ErrRPC with errors.Is.
+type RPCError struct {
+	Code    int
+	Message string
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("%s: %d: %s", ErrRPC, e.Code, e.Message)
+}
+
+func (e *RPCError) Is(target error) bool {
+	return target == ErrRPC
+}
+
+// HTTPError is returned when the node answers with a non-200 HTTP status.
+// RetryAfter is set when the response carried a Retry-After header.
+type HTTPError struct {
+	StatusCode int
+	Body       string
+	RetryAfter time.Duration
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("unexpected HTTP status %d: %s", e.StatusCode, e.Body)
+}
+
+// retryableRPCCodes are JSON-RPC error codes nodes return for transient conditions.
+var retryableRPCCodes = map[int]bool{
+	-32000: true, // generic server error, e.g. "header not found" on a node that is catching up
+	-32005: true, // limit exceeded
+	-32603: true, // internal error
+}
+
+// rpcMethodNotFound is the JSON-RPC error code for a method the node does not serve.
+const rpcMethodNotFound = -32601
+
+// IsRetryable reports whether a request that failed with err may succeed if
+// sent again: transport failures, HTTP 429 and 5xx responses, and transient
+// JSON-RPC errors. Malformed requests and responses are fatal.
+func IsRetryable(err error) bool {
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == http.StatusTooManyRequests || httpErr.StatusCode >= http.StatusInternalServerError
+	}
+
+	var rpcErr *RPCError
+	if errors.As(err, &rpcErr) {
+		return retryableRPCCodes[rpcErr.Code]
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	return errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// isUnavailable reports whether err means the endpoint cannot serve the
+// request yet or at all, while another endpoint might: the block is not known
+// to it or the method is not supported.
+func isUnavailable(err error) bool {
+	var rpcErr *RPCError
+	if errors.As(err, &rpcErr) && rpcErr.Code == rpcMethodNotFound {
+		return true
+	}
+
+	return errors.Is(err, ErrBlockNotFound)
+}
+
+func rpcError(rpcErr *Error) error {
+	return &RPCError{Code: rpcErr.Code, Message: rpcErr.Message}
+}
+
+// parseRetryAfter decodes a Retry-After header given either in seconds or as an HTTP date.
+func parseRetryAfter(header string, now time.Time) time.Duration {
+	if header == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(header); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0)
+	}
+
+	if date, err := http.ParseTime(header); err == nil {
+		return max(date.Sub(now), 0)
+	}
+
+	return 0
+}
@@ -0,0 +1,390 @@
+package ethereum
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+	"trustwallet/internal/model"
+)
+
+var (
+	ErrNoHealthyEndpoints = errors.New("no healthy RPC endpoints")
+	ErrEndpointLagging    = errors.New("endpoint lags behind the other endpoints")
+)
+
+// Strategy decides which healthy endpoint a Pool tries first.
+type Strategy int
+
+const (
+	StrategyRoundRobin Strategy = iota
+	StrategyLowestLatency
+)
+
+const (
+	// DefaultProbation is how long an ejected endpoint is kept out of rotation.
+	DefaultProbation = 30 * time.Second
+	// DefaultMaxLag is how many blocks an endpoint may trail the best known head.
+	DefaultMaxLag = 5
+)
+
+// latencySmoothing is the weight of the newest sample in the latency average.
+const latencySmoothing = 0.3
+
+// EndpointStatus is a snapshot of what a Pool knows about one endpoint.
+type EndpointStatus struct {
+	URL         string
+	Healthy     bool
+	Latency     time.Duration
+	BlockNumber uint64
+	LastError   error
+}
+
+type endpoint struct {
+	client       *Client
+	latency      time.Duration
+	blockNumber  uint64
+	ejectedUntil time.Time
+	lastErr      error
+}
+
+// Pool spreads requests over several RPC endpoints and fails over between
+// them. Endpoints that fail with a retryable error or trail the others' head
+// by more than the allowed lag are ejected, and re-admitted once their
+// probation has passed. Pool offers the same block methods as Client, so it
+// can stand in for it wherever a single endpoint is used.
+type Pool struct {
+	mu        *sync.Mutex
+	endpoints []*endpoint
+	strategy  Strategy
+	probation time.Duration
+	maxLag    uint64
+	next      int
+}
+
+type PoolOption func(*Pool)
+
+func WithStrategy(strategy Strategy) PoolOption {
+	return func(p *Pool) {
+		p.strategy = strategy
+	}
+}
+
+func WithProbation(probation time.Duration) PoolOption {
+	return func(p *Pool) {
+		p.probation = probation
+	}
+}
+
+func WithMaxLag(blocks uint64) PoolOption {
+	return func(p *Pool) {
+		p.maxLag = blocks
+	}
+}
+
+func NewPool(clients []*Client, opts ...PoolOption) *Pool {
+	p := &Pool{
+		mu:        &sync.Mutex{},
+		strategy:  StrategyRoundRobin,
+		probation: DefaultProbation,
+		maxLag:    DefaultMaxLag,
+	}
+
+	for _, client := range clients {
+		p.endpoints = append(p.endpoints, &endpoint{client: client})
+	}
+
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+func (p *Pool) GetLatestBlockNumber(ctx context.Context) (uint64, error) {
+	var blockNumber uint64
+	err := p.do(ctx, 0, func(e *endpoint) error {
+		number, err := e.client.GetLatestBlockNumber(ctx)
+		if err != nil {
+			return err
+		}
+
+		if err := p.recordHead(e, number); err != nil {
+			return err
+		}
+
+		blockNumber = number
+		return nil
+	})
+
+	return blockNumber, err
+}
+
+func (p *Pool) GetBlockNumberByTag(ctx context.Context, tag model.BlockTag) (uint64, error) {
+	var blockNumber uint64
+	err := p.do(ctx, 0, func(e *endpoint) error {
+		number, err := e.client.GetBlockNumberByTag(ctx, tag)
+		blockNumber = number
+		return err
+	})
+
+	return blockNumber, err
+}
+
+func (p *Pool) GetBlockByNumber(ctx context.Context, blockNumber uint64) (model.Block, error) {
+	var block model.Block
+	err := p.do(ctx, blockNumber, func(e *endpoint) error {
+		b, err := e.client.GetBlockByNumber(ctx, blockNumber)
+		block = b
+		return err
+	})
+
+	return block, err
+}
+
+func (p *Pool) GetBlocksByNumberRange(ctx context.Context, fromBlock, toBlock uint64) ([]model.Block, error) {
+	var blocks []model.Block
+	err := p.do(ctx, toBlock, func(e *endpoint) error {
+		b, err := e.client.GetBlocksByNumberRange(ctx, fromBlock, toBlock)
+		blocks = b
+		return err
+	})
+
+	return blocks, err
+}
+
+func (p *Pool) GetTransactionsByBlockNumber(ctx context.Context, blockNumber uint64) ([]model.Transaction, error) {
+	var transactions []model.Transaction
+	err := p.do(ctx, blockNumber, func(e *endpoint) error {
+		txs, err := e.client.GetTransactionsByBlockNumber(ctx, blockNumber)
+		transactions = txs
+		return err
+	})
+
+	return transactions, err
+}
+
+func (p *Pool) GetBlockReceipts(ctx context.Context, blockNumber uint64) ([]model.Receipt, error) {
+	var receipts []model.Receipt
+	err := p.do(ctx, blockNumber, func(e *endpoint) error {
+		r, err := e.client.GetBlockReceipts(ctx, blockNumber)
+		receipts = r
+		return err
+	})
+
+	return receipts, err
+}
+
+func (p *Pool) GetLogs(ctx context.Context, blockNumber uint64, topics []string) ([]model.Log, error) {
+	var logs []model.Log
+	err := p.do(ctx, blockNumber, func(e *endpoint) error {
+		l, err := e.client.GetLogs(ctx, blockNumber, topics)
+		logs = l
+		return err
+	})
+
+	return logs, err
+}
+
+func (p *Pool) GetInternalTransfers(ctx context.Context, blockNumber uint64, api model.TraceAPI) ([]model.InternalTransfer, error) {
+	var transfers []model.InternalTransfer
+	err := p.do(ctx, blockNumber, func(e *endpoint) error {
+		t, err := e.client.GetInternalTransfers(ctx, blockNumber, api)
+		transfers = t
+		return err
+	})
+
+	return transfers, err
+}
+
+// CheckHealth probes every endpoint for its head block, refreshing latency and
+// ejecting endpoints that fail or lag behind. Call it periodically so lagging
+// endpoints are noticed even when they are not picked for GetLatestBlockNumber.
+func (p *Pool) CheckHealth(ctx context.Context) {
+	heads := make([]uint64, len(p.endpoints))
+	errs := make([]error, len(p.endpoints))
+
+	wg := sync.WaitGroup{}
+	for i, e := range p.endpoints {
+		wg.Add(1)
+		go func(i int, e *endpoint) {
+			defer wg.Done()
+
+			start := time.Now()
+			heads[i], errs[i] = e.client.GetLatestBlockNumber(ctx)
+			if errs[i] == nil {
+				p.succeeded(e, time.Since(start))
+			}
+		}(i, e)
+	}
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		return
+	}
+
+	for i, e := range p.endpoints {
+		if errs[i] == nil {
+			errs[i] = p.recordHead(e, heads[i])
+		}
+		if errs[i] != nil {
+			p.eject(e, errs[i])
+		}
+	}
+}
+
+// Status returns a snapshot of every endpoint in the pool.
+func (p *Pool) Status() []EndpointStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+
+	statuses := make([]EndpointStatus, 0, len(p.endpoints))
+	for _, e := range p.endpoints {
+		statuses = append(statuses, EndpointStatus{
+			URL:         e.client.URL(),
+			Healthy:     !now.Before(e.ejectedUntil),
+			Latency:     e.latency,
+			BlockNumber: e.blockNumber,
+			LastError:   e.lastErr,
+		})
+	}
+
+	return statuses
+}
+
+// do runs call against the healthy endpoints in routing order until one
+// succeeds. Endpoints known to be behind minBlock are skipped. Retryable
+// failures eject the endpoint and fail over to the next one. An endpoint that
+// does not have the block yet or does not serve the method is passed over
+// without being ejected. Other errors are returned as they are, because
+// another endpoint would fail the same way. Failures caused by ctx being done
+// never eject an endpoint. While every endpoint is ejected, the one ejected
+// longest ago is tried rather than failing outright.
+func (p *Pool) do(ctx context.Context, minBlock uint64, call func(e *endpoint) error) error {
+	candidates, fallback := p.candidates(minBlock)
+
+	var errs []error
+	if fallback {
+		errs = append(errs, ErrNoHealthyEndpoints)
+	}
+
+	for _, e := range candidates {
+		start := time.Now()
+
+		err := call(e)
+		if err == nil {
+			p.succeeded(e, time.Since(start))
+			return nil
+		}
+
+		if ctx.Err() != nil {
+			return err
+		}
+
+		switch {
+		case IsRetryable(err) || errors.Is(err, ErrEndpointLagging):
+			p.eject(e, err)
+		case !isUnavailable(err):
+			return err
+		}
+
+		errs = append(errs, fmt.Errorf("%s: %w", e.client.URL(), err))
+	}
+
+	if len(errs) == 0 {
+		return ErrNoHealthyEndpoints
+	}
+
+	return errors.Join(errs...)
+}
+
+// candidates returns the endpoints to try in routing order. When every
+// endpoint is ejected it returns the one ejected longest ago and reports that
+// it fell back to it.
+func (p *Pool) candidates(minBlock uint64) ([]*endpoint, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+
+	var (
+		healthy []*endpoint
+		oldest  *endpoint
+	)
+	for _, e := range p.endpoints {
+		if e.blockNumber != 0 && e.blockNumber < minBlock {
+			continue
+		}
+		if now.Before(e.ejectedUntil) {
+			if oldest == nil || e.ejectedUntil.Before(oldest.ejectedUntil) {
+				oldest = e
+			}
+			continue
+		}
+		healthy = append(healthy, e)
+	}
+
+	if len(healthy) == 0 {
+		if oldest == nil {
+			return nil, false
+		}
+		return []*endpoint{oldest}, true
+	}
+
+	switch p.strategy {
+	case StrategyLowestLatency:
+		sort.SliceStable(healthy, func(i, j int) bool {
+			return healthy[i].latency < healthy[j].latency
+		})
+	default:
+		start := p.next % len(healthy)
+		p.next++
+		healthy = append(append([]*endpoint(nil), healthy[start:]...), healthy[:start]...)
+	}
+
+	return healthy, false
+}
+
+// recordHead remembers the head an endpoint reported and returns
+// ErrEndpointLagging if it trails the best known head by more than maxLag.
+func (p *Pool) recordHead(e *endpoint, blockNumber uint64) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	e.blockNumber = blockNumber
+
+	var best uint64
+	for _, other := range p.endpoints {
+		best = max(best, other.blockNumber)
+	}
+
+	if best-blockNumber > p.maxLag {
+		return fmt.Errorf("%w: at block %d, best known head is %d", ErrEndpointLagging, blockNumber, best)
+	}
+
+	return nil
+}
+
+func (p *Pool) succeeded(e *endpoint, latency time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if e.latency == 0 {
+		e.latency = latency
+	} else {
+		e.latency = time.Duration(latencySmoothing*float64(latency) + (1-latencySmoothing)*float64(e.latency))
+	}
+	e.lastErr = nil
+}
+
+func (p *Pool) eject(e *endpoint, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	e.ejectedUntil = time.Now().Add(p.probation)
+	e.lastErr = err
+}
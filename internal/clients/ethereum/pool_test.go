@@ -0,0 +1,254 @@
+package ethereum_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+	"trustwallet/internal/clients/ethereum"
+	ethereumParser "trustwallet/internal/parser/ethereum"
+)
+
+var _ ethereumParser.EthereumClient = (*ethereum.Pool)(nil)
+
+// fakeNode is an in-process RPC endpoint whose head, latency and health can be
+// changed while a test runs.
+type fakeNode struct {
+	server   *httptest.Server
+	head     atomic.Uint64
+	status   atomic.Int32
+	delay    atomic.Int64
+	requests atomic.Int32
+	// result, when set, is answered instead of the head.
+	result atomic.Pointer[string]
+}
+
+func newFakeNode(t *testing.T, head uint64) *fakeNode {
+	node := &fakeNode{}
+	node.head.Store(head)
+	node.status.Store(http.StatusOK)
+
+	node.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		node.requests.Add(1)
+		time.Sleep(time.Duration(node.delay.Load()))
+
+		if status := int(node.status.Load()); status != http.StatusOK {
+			http.Error(w, http.StatusText(status), status)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if result := node.result.Load(); result != nil {
+			_, err := fmt.Fprint(w, *result)
+			assert.NoError(t, err)
+			return
+		}
+
+		_, err := fmt.Fprintf(w, `{"jsonrpc": "2.0", "id": 1, "result": "0x%x"}`, node.head.Load())
+		assert.NoError(t, err)
+	}))
+	t.Cleanup(node.server.Close)
+
+	return node
+}
+
+func newTestPool(nodes []*fakeNode, opts ...ethereum.PoolOption) *ethereum.Pool {
+	clients := make([]*ethereum.Client, 0, len(nodes))
+	for _, node := range nodes {
+		clients = append(clients, ethereum.New(node.server.URL, node.server.Client()))
+	}
+
+	return ethereum.NewPool(clients, opts...)
+}
+
+func TestPool_Failover(t *testing.T) {
+	failing, healthy := newFakeNode(t, 100), newFakeNode(t, 100)
+	failing.status.Store(http.StatusServiceUnavailable)
+
+	pool := newTestPool([]*fakeNode{failing, healthy})
+
+	for i := 0; i < 3; i++ {
+		blockNumber, err := pool.GetLatestBlockNumber(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(100), blockNumber)
+	}
+
+	assert.Equal(t, int32(1), failing.requests.Load(), "failing endpoint should be ejected after its first error")
+	assert.Equal(t, int32(3), healthy.requests.Load())
+
+	status := pool.Status()
+	assert.False(t, status[0].Healthy)
+	assert.Error(t, status[0].LastError)
+	assert.True(t, status[1].Healthy)
+}
+
+func TestPool_RoundRobin(t *testing.T) {
+	first, second := newFakeNode(t, 100), newFakeNode(t, 100)
+	pool := newTestPool([]*fakeNode{first, second})
+
+	for i := 0; i < 4; i++ {
+		_, err := pool.GetLatestBlockNumber(context.Background())
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, int32(2), first.requests.Load())
+	assert.Equal(t, int32(2), second.requests.Load())
+}
+
+func TestPool_LowestLatency(t *testing.T) {
+	slow, fast := newFakeNode(t, 100), newFakeNode(t, 100)
+	slow.delay.Store(int64(20 * time.Millisecond))
+
+	pool := newTestPool([]*fakeNode{slow, fast}, ethereum.WithStrategy(ethereum.StrategyLowestLatency))
+	pool.CheckHealth(context.Background())
+
+	for i := 0; i < 3; i++ {
+		_, err := pool.GetLatestBlockNumber(context.Background())
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, int32(1), slow.requests.Load(), "slow endpoint should only see the health check")
+	assert.Equal(t, int32(4), fast.requests.Load())
+}
+
+func TestPool_EjectsLaggingEndpoint(t *testing.T) {
+	ahead, behind := newFakeNode(t, 100), newFakeNode(t, 90)
+	pool := newTestPool([]*fakeNode{ahead, behind}, ethereum.WithMaxLag(5))
+
+	pool.CheckHealth(context.Background())
+
+	status := pool.Status()
+	assert.True(t, status[0].Healthy)
+	assert.False(t, status[1].Healthy)
+	assert.ErrorIs(t, status[1].LastError, ethereum.ErrEndpointLagging)
+	assert.Equal(t, uint64(90), status[1].BlockNumber)
+
+	// Requests only go to the endpoint that is up to date.
+	for i := 0; i < 2; i++ {
+		blockNumber, err := pool.GetLatestBlockNumber(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(100), blockNumber)
+	}
+}
+
+func TestPool_ReadmitsAfterProbation(t *testing.T) {
+	node := newFakeNode(t, 100)
+	node.status.Store(http.StatusBadGateway)
+
+	pool := newTestPool([]*fakeNode{node}, ethereum.WithProbation(20*time.Millisecond))
+
+	_, err := pool.GetLatestBlockNumber(context.Background())
+	assert.Error(t, err)
+
+	// While it is the only endpoint it is still tried as a last resort.
+	_, err = pool.GetLatestBlockNumber(context.Background())
+	assert.ErrorIs(t, err, ethereum.ErrNoHealthyEndpoints)
+	assert.Equal(t, int32(2), node.requests.Load())
+
+	node.status.Store(http.StatusOK)
+
+	assert.Eventually(t, func() bool {
+		blockNumber, err := pool.GetLatestBlockNumber(context.Background())
+		return err == nil && blockNumber == 100
+	}, time.Second, 5*time.Millisecond)
+}
+
+func TestPool_AllEjectedFallsBackToLongestEjected(t *testing.T) {
+	first, second := newFakeNode(t, 100), newFakeNode(t, 100)
+	first.status.Store(http.StatusServiceUnavailable)
+	second.status.Store(http.StatusServiceUnavailable)
+
+	pool := newTestPool([]*fakeNode{first, second})
+
+	// Round robin tries the first endpoint first, so it is ejected first.
+	_, err := pool.GetLatestBlockNumber(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), first.requests.Load())
+	assert.Equal(t, int32(1), second.requests.Load())
+
+	first.status.Store(http.StatusOK)
+	second.status.Store(http.StatusOK)
+
+	blockNumber, err := pool.GetLatestBlockNumber(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(100), blockNumber)
+	assert.Equal(t, int32(2), first.requests.Load(), "the endpoint ejected longest ago should be tried")
+	assert.Equal(t, int32(1), second.requests.Load())
+
+	// A failing fallback is ejected again, so the other one is tried next.
+	first.status.Store(http.StatusServiceUnavailable)
+
+	_, err = pool.GetLatestBlockNumber(context.Background())
+	assert.ErrorIs(t, err, ethereum.ErrNoHealthyEndpoints)
+
+	_, err = pool.GetLatestBlockNumber(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), first.requests.Load())
+	assert.Equal(t, int32(2), second.requests.Load())
+}
+
+func TestPool_FatalErrorDoesNotFailOver(t *testing.T) {
+	first, second := newFakeNode(t, 100), newFakeNode(t, 100)
+	first.status.Store(http.StatusBadRequest)
+	second.status.Store(http.StatusBadRequest)
+
+	pool := newTestPool([]*fakeNode{first, second})
+
+	_, err := pool.GetLatestBlockNumber(context.Background())
+
+	var httpErr *ethereum.HTTPError
+	assert.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, int32(1), first.requests.Load()+second.requests.Load(), "a request error should not be retried on another endpoint")
+	for _, status := range pool.Status() {
+		assert.True(t, status.Healthy)
+	}
+}
+
+func TestPool_FailsOverUnavailable(t *testing.T) {
+	block := `{"jsonrpc": "2.0", "id": 1, "result": {"number": "0x64", "hash": "0xBlock100", "parentHash": "0xBlock99", "timestamp": "0x0", "transactions": []}}`
+
+	tests := []struct {
+		name   string
+		result string
+	}{
+		{"block not found yet", `{"jsonrpc": "2.0", "id": 1, "result": null}`},
+		{"method not supported", `{"jsonrpc": "2.0", "id": 1, "error": {"code": -32601, "message": "the method does not exist"}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			unavailable, available := newFakeNode(t, 100), newFakeNode(t, 100)
+			unavailable.result.Store(&tt.result)
+			available.result.Store(&block)
+
+			pool := newTestPool([]*fakeNode{unavailable, available})
+
+			got, err := pool.GetBlockByNumber(context.Background(), 100)
+			assert.NoError(t, err)
+			assert.Equal(t, "0xBlock100", got.Hash)
+			assert.Equal(t, int32(1), unavailable.requests.Load(), "the first endpoint should have been tried")
+
+			for _, status := range pool.Status() {
+				assert.True(t, status.Healthy, "an endpoint that cannot serve a request yet should not be ejected")
+			}
+		})
+	}
+}
+
+func TestPool_BlockNotFoundEverywhere(t *testing.T) {
+	result := `{"jsonrpc": "2.0", "id": 1, "result": null}`
+	first, second := newFakeNode(t, 100), newFakeNode(t, 100)
+	first.result.Store(&result)
+	second.result.Store(&result)
+
+	pool := newTestPool([]*fakeNode{first, second})
+
+	_, err := pool.GetBlockByNumber(context.Background(), 101)
+
+	assert.ErrorIs(t, err, ethereum.ErrBlockNotFound)
+	assert.Equal(t, int32(2), first.requests.Load()+second.requests.Load())
+}
@@ -0,0 +1,143 @@
+package ethereum
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimit is a token bucket budget: RequestsPerSecond tokens are added per
+// second, and at most Burst tokens can be saved up.
+type RateLimit struct {
+	RequestsPerSecond float64
+	Burst             int
+}
+
+type tokenBucket struct {
+	limit  RateLimit
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(limit RateLimit, now time.Time) *tokenBucket {
+	limit.Burst = max(limit.Burst, 1)
+
+	return &tokenBucket{
+		limit:  limit,
+		tokens: float64(limit.Burst),
+		last:   now,
+	}
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	b.tokens = min(float64(b.limit.Burst), b.tokens+now.Sub(b.last).Seconds()*b.limit.RequestsPerSecond)
+	b.last = now
+}
+
+// delay returns how long until the bucket holds a whole token.
+func (b *tokenBucket) delay() time.Duration {
+	if b.tokens >= 1 {
+		return 0
+	}
+
+	return time.Duration((1 - b.tokens) / b.limit.RequestsPerSecond * float64(time.Second))
+}
+
+// RateLimiter keeps a client within a provider's request budget. Every request
+// needs a token from the overall budget and, if the method has one, from the
+// method's own budget. A provider asking to back off via Retry-After pauses
+// all requests.
+type RateLimiter struct {
+	mu           *sync.Mutex
+	overall      *tokenBucket
+	methods      map[string]*tokenBucket
+	blockedUntil time.Time
+}
+
+// NewRateLimiter creates a limiter with an overall budget and optional
+// per-method budgets. A zero overall limit leaves only the method budgets, and
+// a method budget of zero requests per second is ignored.
+func NewRateLimiter(limit RateLimit, methodLimits map[string]RateLimit) *RateLimiter {
+	now := time.Now()
+
+	l := &RateLimiter{
+		mu:      &sync.Mutex{},
+		methods: make(map[string]*tokenBucket, len(methodLimits)),
+	}
+
+	if limit.RequestsPerSecond > 0 {
+		l.overall = newTokenBucket(limit, now)
+	}
+
+	for method, methodLimit := range methodLimits {
+		if methodLimit.RequestsPerSecond > 0 {
+			l.methods[method] = newTokenBucket(methodLimit, now)
+		}
+	}
+
+	return l
+}
+
+// Wait blocks until a request for method fits the budget, or ctx is done.
+func (l *RateLimiter) Wait(ctx context.Context, method string) error {
+	for {
+		delay := l.take(method)
+		if delay == 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// Backoff pauses all requests for d, as asked for by a Retry-After header.
+func (l *RateLimiter) Backoff(d time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if until := time.Now().Add(d); until.After(l.blockedUntil) {
+		l.blockedUntil = until
+	}
+}
+
+// take consumes the tokens for a request and returns 0, or returns how long to
+// wait before trying again without consuming anything.
+func (l *RateLimiter) take(method string) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if now.Before(l.blockedUntil) {
+		return l.blockedUntil.Sub(now)
+	}
+
+	var buckets []*tokenBucket
+	if l.overall != nil {
+		buckets = append(buckets, l.overall)
+	}
+	if bucket, ok := l.methods[method]; ok {
+		buckets = append(buckets, bucket)
+	}
+
+	var delay time.Duration
+	for _, bucket := range buckets {
+		bucket.refill(now)
+		delay = max(delay, bucket.delay())
+	}
+
+	if delay > 0 {
+		return delay
+	}
+
+	for _, bucket := range buckets {
+		bucket.tokens--
+	}
+
+	return 0
+}
@@ -0,0 +1,141 @@
+package ethereum_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+	"trustwallet/internal/clients/ethereum"
+)
+
+func TestRateLimiter_Burst(t *testing.T) {
+	limiter := ethereum.NewRateLimiter(ethereum.RateLimit{RequestsPerSecond: 20, Burst: 3}, nil)
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, limiter.Wait(context.Background(), "eth_blockNumber"))
+	}
+	assert.Less(t, time.Since(start), 25*time.Millisecond, "burst should not wait")
+
+	assert.NoError(t, limiter.Wait(context.Background(), "eth_blockNumber"))
+	assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond, "requests beyond the burst should wait for a token")
+}
+
+func TestRateLimiter_MethodBudget(t *testing.T) {
+	limiter := ethereum.NewRateLimiter(ethereum.RateLimit{}, map[string]ethereum.RateLimit{
+		"eth_getBlockByNumber": {RequestsPerSecond: 1, Burst: 1},
+	})
+
+	assert.NoError(t, limiter.Wait(context.Background(), "eth_getBlockByNumber"))
+
+	// Methods without a budget of their own are not held back.
+	start := time.Now()
+	for i := 0; i < 10; i++ {
+		assert.NoError(t, limiter.Wait(context.Background(), "eth_blockNumber"))
+	}
+	assert.Less(t, time.Since(start), 25*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	err := limiter.Wait(ctx, "eth_getBlockByNumber")
+	assert.ErrorIs(t, err, context.DeadlineExceeded, "exhausted method budget should wait until the context is done")
+}
+
+func TestRateLimiter_ZeroMethodBudgetIgnored(t *testing.T) {
+	limiter := ethereum.NewRateLimiter(ethereum.RateLimit{RequestsPerSecond: 20, Burst: 3}, map[string]ethereum.RateLimit{
+		"eth_getLogs": {RequestsPerSecond: 0, Burst: 1},
+	})
+
+	// Only the overall budget applies.
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, limiter.Wait(context.Background(), "eth_getLogs"))
+	}
+	assert.Less(t, time.Since(start), 25*time.Millisecond, "burst should not wait")
+
+	assert.NoError(t, limiter.Wait(context.Background(), "eth_getLogs"))
+	assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond, "requests beyond the overall burst should wait for a token")
+}
+
+func TestRateLimiter_Backoff(t *testing.T) {
+	limiter := ethereum.NewRateLimiter(ethereum.RateLimit{RequestsPerSecond: 1000, Burst: 10}, nil)
+	limiter.Backoff(30 * time.Millisecond)
+
+	start := time.Now()
+	assert.NoError(t, limiter.Wait(context.Background(), "eth_blockNumber"))
+	assert.GreaterOrEqual(t, time.Since(start), 30*time.Millisecond)
+}
+
+func TestRateLimiter_WaitCancelled(t *testing.T) {
+	limiter := ethereum.NewRateLimiter(ethereum.RateLimit{RequestsPerSecond: 1, Burst: 1}, nil)
+	limiter.Backoff(time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+
+	err := limiter.Wait(ctx, "eth_blockNumber")
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestClient_HonorsRetryAfter(t *testing.T) {
+	requests := &atomic.Int32{}
+	var first, second time.Time
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			first = time.Now()
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, "Too Many Requests", http.StatusTooManyRequests)
+			return
+		}
+
+		second = time.Now()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jsonrpc": "2.0", "id": 1, "result": "0x10"}`))
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	limiter := ethereum.NewRateLimiter(ethereum.RateLimit{RequestsPerSecond: 100, Burst: 10}, nil)
+	client := ethereum.New(server.URL, server.Client(), ethereum.WithRetryPolicy(fastRetries), ethereum.WithRateLimiter(limiter))
+
+	blockNumber, err := client.GetLatestBlockNumber(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(16), blockNumber)
+	assert.Equal(t, int32(2), requests.Load())
+	assert.GreaterOrEqual(t, second.Sub(first), 900*time.Millisecond, "retry should wait for Retry-After")
+}
+
+func TestClient_RateLimited(t *testing.T) {
+	requests := &atomic.Int32{}
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jsonrpc": "2.0", "id": 1, "result": "0x10"}`))
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	limiter := ethereum.NewRateLimiter(ethereum.RateLimit{RequestsPerSecond: 50, Burst: 1}, nil)
+	client := ethereum.New(server.URL, server.Client(), ethereum.WithRateLimiter(limiter))
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		_, err := client.GetLatestBlockNumber(context.Background())
+		assert.NoError(t, err)
+	}
+
+	assert.GreaterOrEqual(t, time.Since(start), 55*time.Millisecond, "4 requests at 50 rps with burst 1 take at least 60ms")
+	assert.Equal(t, int32(4), requests.Load())
+}
@@ -0,0 +1,85 @@
+package ethereum
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+)
+
+// RetryPolicy controls how a Client retries failed requests. Backoff doubles
+// after every attempt, starting at InitialBackoff and capped at MaxBackoff, and
+// is randomly shortened by up to Jitter (a fraction between 0 and 1).
+type RetryPolicy struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Jitter         float64
+	// Retryable classifies errors; IsRetryable is used when nil.
+	Retryable func(error) bool
+}
+
+// DefaultRetryPolicy is a reasonable policy for public RPC providers.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    5,
+	InitialBackoff: 200 * time.Millisecond,
+	MaxBackoff:     5 * time.Second,
+	Jitter:         0.2,
+}
+
+// noRetry makes a single attempt; it is the policy of clients built without WithRetryPolicy.
+var noRetry = RetryPolicy{MaxAttempts: 1}
+
+func (p RetryPolicy) retryable(err error) bool {
+	if p.Retryable != nil {
+		return p.Retryable(err)
+	}
+
+	return IsRetryable(err)
+}
+
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	backoff := p.InitialBackoff
+	for i := 1; i < attempt && backoff < p.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	backoff = min(backoff, p.MaxBackoff)
+
+	return backoff - time.Duration(p.Jitter*rand.Float64()*float64(backoff))
+}
+
+// retry runs do until it succeeds, fails with an error that is not retryable,
+// the policy runs out of attempts or ctx is done. A Retry-After longer than
+// the backoff is honored. If ctx is done while backing off, the error wraps
+// both ctx's error and the last attempt's.
+func (c *Client) retry(ctx context.Context, do func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := do()
+		if err == nil || ctx.Err() != nil || !c.retryPolicy.retryable(err) {
+			return err
+		}
+
+		if attempt >= c.retryPolicy.MaxAttempts {
+			if attempt > 1 {
+				return fmt.Errorf("giving up after %d attempts: %w", attempt, err)
+			}
+			return err
+		}
+
+		backoff := c.retryPolicy.backoff(attempt)
+
+		var httpErr *HTTPError
+		if errors.As(err, &httpErr) && httpErr.RetryAfter > backoff {
+			backoff = httpErr.RetryAfter
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("%w after attempt %d: %w", ctx.Err(), attempt, err)
+		case <-timer.C:
+		}
+	}
+}
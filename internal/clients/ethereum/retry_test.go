@@ -0,0 +1,208 @@
+package ethereum_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+	"trustwallet/internal/clients/ethereum"
+)
+
+var fastRetries = ethereum.RetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: time.Millisecond,
+	MaxBackoff:     5 * time.Millisecond,
+	Jitter:         0.5,
+}
+
+// flakyServer fails the first failures requests with fail and then answers eth_blockNumber.
+func flakyServer(failures int32, fail http.HandlerFunc) (*httptest.Server, *atomic.Int32) {
+	requests := &atomic.Int32{}
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) <= failures {
+			fail(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"jsonrpc": "2.0", "id": 1, "result": "0x10"}`))
+	}
+
+	return httptest.NewServer(http.HandlerFunc(handler)), requests
+}
+
+func rpcErrorHandler(code int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"jsonrpc": "2.0", "id": 1, "error": {"code": %d, "message": "error"}}`, code)
+	}
+}
+
+func TestClient_Retry(t *testing.T) {
+	tests := []struct {
+		name         string
+		failures     int32
+		fail         http.HandlerFunc
+		wantErr      bool
+		wantRequests int32
+	}{
+		{
+			name:     "Retry HTTP 503",
+			failures: 2,
+			fail: func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "Service Unavailable", http.StatusServiceUnavailable)
+			},
+			wantRequests: 3,
+		},
+		{
+			name:     "Retry HTTP 429",
+			failures: 1,
+			fail: func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "Too Many Requests", http.StatusTooManyRequests)
+			},
+			wantRequests: 2,
+		},
+		{
+			name:         "Retry limit exceeded RPC error",
+			failures:     1,
+			fail:         rpcErrorHandler(-32005),
+			wantRequests: 2,
+		},
+		{
+			name:     "Give up after max attempts",
+			failures: 3,
+			fail: func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "Bad Gateway", http.StatusBadGateway)
+			},
+			wantErr:      true,
+			wantRequests: 3,
+		},
+		{
+			name:     "Do not retry HTTP 400",
+			failures: 1,
+			fail: func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "Bad Request", http.StatusBadRequest)
+			},
+			wantErr:      true,
+			wantRequests: 1,
+		},
+		{
+			name:         "Do not retry method not found",
+			failures:     1,
+			fail:         rpcErrorHandler(-32601),
+			wantErr:      true,
+			wantRequests: 1,
+		},
+		{
+			name:     "Do not retry malformed response",
+			failures: 1,
+			fail: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(`Invalid JSON`))
+			},
+			wantErr:      true,
+			wantRequests: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, requests := flakyServer(tt.failures, tt.fail)
+			defer server.Close()
+
+			client := ethereum.New(server.URL, server.Client(), ethereum.WithRetryPolicy(fastRetries))
+
+			blockNumber, err := client.GetLatestBlockNumber(context.Background())
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, uint64(16), blockNumber)
+			}
+			assert.Equal(t, tt.wantRequests, requests.Load())
+		})
+	}
+}
+
+func TestClient_RetryCancelledDuringBackoff(t *testing.T) {
+	server, requests := flakyServer(1, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Service Unavailable", http.StatusServiceUnavailable)
+	})
+	defer server.Close()
+
+	policy := fastRetries
+	policy.InitialBackoff = time.Hour
+	policy.MaxBackoff = time.Hour
+	client := ethereum.New(server.URL, server.Client(), ethereum.WithRetryPolicy(policy))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := client.GetLatestBlockNumber(ctx)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	var httpErr *ethereum.HTTPError
+	assert.ErrorAs(t, err, &httpErr, "the last transport error should be kept")
+	assert.Equal(t, int32(1), requests.Load())
+}
+
+func TestClient_NoRetryByDefault(t *testing.T) {
+	server, requests := flakyServer(1, func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Service Unavailable", http.StatusServiceUnavailable)
+	})
+	defer server.Close()
+
+	client := ethereum.New(server.URL, server.Client())
+
+	_, err := client.GetLatestBlockNumber(context.Background())
+
+	var httpErr *ethereum.HTTPError
+	assert.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, http.StatusServiceUnavailable, httpErr.StatusCode)
+	assert.Equal(t, int32(1), requests.Load())
+}
+
+func TestClient_RetryCustomClassification(t *testing.T) {
+	server, requests := flakyServer(1, rpcErrorHandler(-32601))
+	defer server.Close()
+
+	policy := fastRetries
+	policy.Retryable = func(err error) bool { return errors.Is(err, ethereum.ErrRPC) }
+
+	client := ethereum.New(server.URL, server.Client(), ethereum.WithRetryPolicy(policy))
+
+	_, err := client.GetLatestBlockNumber(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), requests.Load())
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "HTTP 500", err: &ethereum.HTTPError{StatusCode: http.StatusInternalServerError}, want: true},
+		{name: "HTTP 429", err: &ethereum.HTTPError{StatusCode: http.StatusTooManyRequests}, want: true},
+		{name: "HTTP 404", err: &ethereum.HTTPError{StatusCode: http.StatusNotFound}, want: false},
+		{name: "Internal RPC error", err: &ethereum.RPCError{Code: -32603}, want: true},
+		{name: "Invalid params", err: &ethereum.RPCError{Code: -32602}, want: false},
+		{name: "Wrapped RPC error", err: fmt.Errorf("block 1: %w", &ethereum.RPCError{Code: -32005}), want: true},
+		{name: "Network error", err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}, want: true},
+		{name: "Truncated response", err: io.ErrUnexpectedEOF, want: true},
+		{name: "Other error", err: errors.New("invalid hex quantity"), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ethereum.IsRetryable(tt.err))
+		})
+	}
+}
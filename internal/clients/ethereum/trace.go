@@ -0,0 +1,283 @@
+package ethereum
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"trustwallet/internal/model"
+)
+
+// GetInternalTransfers traces every transaction of a block through the given
+// API and returns the ETH transfers made by nested calls. Top-level calls are
+// the transactions themselves and are left out, as are calls that moved no
+// value and calls that were reverted.
+func (c *Client) GetInternalTransfers(ctx context.Context, blockNumber uint64, api model.TraceAPI) ([]model.InternalTransfer, error) {
+	switch api {
+	case model.TraceAPIDebug:
+		return c.debugTraceBlock(ctx, blockNumber)
+	case model.TraceAPITrace:
+		return c.traceBlock(ctx, blockNumber)
+	default:
+		return nil, fmt.Errorf("unsupported trace API %q", api)
+	}
+}
+
+func (c *Client) debugTraceBlock(ctx context.Context, blockNumber uint64) ([]model.InternalTransfer, error) {
+	rawJson, err := c.call(ctx, "debug_traceBlockByNumber", []interface{}{toHex(blockNumber), TracerConfig{Tracer: "callTracer"}})
+	if err != nil {
+		return nil, err
+	}
+
+	var tracesResp []TransactionTrace
+	if err := json.Unmarshal(rawJson, &tracesResp); err != nil {
+		return nil, err
+	}
+
+	var transfers []model.InternalTransfer
+	for i, trace := range tracesResp {
+		if trace.Error != "" {
+			return nil, fmt.Errorf("trace of transaction %d: %s", i, trace.Error)
+		}
+
+		var index uint64
+		var walk func(frame CallFrame, depth int) error
+		walk = func(frame CallFrame, depth int) error {
+			frameIndex := index
+			index++
+
+			if frame.Error != "" {
+				// A reverted frame undoes everything below it, but its
+				// descendants still take up indexes.
+				index += frame.size() - 1
+				return nil
+			}
+
+			if depth > 0 {
+				transfer, ok, err := frame.toModel()
+				if err != nil {
+					return fmt.Errorf("call %d of transaction %d: %w", frameIndex, i, err)
+				}
+
+				if ok {
+					transfer.TransactionHash = trace.TxHash
+					transfer.TransactionIndex = uint64(i)
+					transfer.Index = frameIndex
+					transfers = append(transfers, transfer)
+				}
+			}
+
+			for _, call := range frame.Calls {
+				if err := walk(call, depth+1); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		}
+
+		if err := walk(trace.Result, 0); err != nil {
+			return nil, err
+		}
+	}
+
+	return transfers, nil
+}
+
+func (c *Client) traceBlock(ctx context.Context, blockNumber uint64) ([]model.InternalTransfer, error) {
+	rawJson, err := c.call(ctx, "trace_block", []interface{}{toHex(blockNumber)})
+	if err != nil {
+		return nil, err
+	}
+
+	var tracesResp []Trace
+	if err := json.Unmarshal(rawJson, &tracesResp); err != nil {
+		return nil, err
+	}
+
+	var (
+		transfers []model.InternalTransfer
+		reverted  [][]int
+		index     uint64
+		lastTx    *uint64
+	)
+	for _, trace := range tracesResp {
+		if trace.TransactionPosition == nil {
+			// Block and uncle rewards do not belong to a transaction.
+			continue
+		}
+
+		if lastTx == nil || *lastTx != *trace.TransactionPosition {
+			lastTx = trace.TransactionPosition
+			reverted = reverted[:0]
+			index = 0
+		}
+
+		traceIndex := index
+		index++
+
+		if trace.Error != "" {
+			reverted = append(reverted, trace.TraceAddress)
+			continue
+		}
+
+		if len(trace.TraceAddress) == 0 || descendsFromAny(trace.TraceAddress, reverted) {
+			continue
+		}
+
+		transfer, ok, err := trace.toModel()
+		if err != nil {
+			return nil, fmt.Errorf("trace %v of transaction %s: %w", trace.TraceAddress, trace.TransactionHash, err)
+		}
+
+		if ok {
+			transfer.TransactionHash = trace.TransactionHash
+			transfer.TransactionIndex = *trace.TransactionPosition
+			transfer.Index = traceIndex
+			transfers = append(transfers, transfer)
+		}
+	}
+
+	return transfers, nil
+}
+
+// TracerConfig selects the tracer debug_traceBlockByNumber runs.
+type TracerConfig struct {
+	Tracer string `json:"tracer"`
+}
+
+// TransactionTrace is the result of tracing one transaction with debug_traceBlockByNumber.
+type TransactionTrace struct {
+	TxHash string    `json:"txHash"`
+	Result CallFrame `json:"result"`
+	Error  string    `json:"error,omitempty"`
+}
+
+// CallFrame is a call as reported by the callTracer, with the calls it made nested in Calls.
+type CallFrame struct {
+	Type  string        `json:"type"`
+	From  model.Address `json:"from"`
+	To    model.Address `json:"to"`
+	Value string        `json:"value,omitempty"`
+	Error string        `json:"error,omitempty"`
+	Calls []CallFrame   `json:"calls,omitempty"`
+}
+
+// size returns the number of frames in the call tree rooted at f.
+func (f CallFrame) size() uint64 {
+	size := uint64(1)
+	for _, call := range f.Calls {
+		size += call.size()
+	}
+
+	return size
+}
+
+// toModel converts a frame that moved value into an internal transfer. It
+// reports false for frames that moved none.
+func (f CallFrame) toModel() (model.InternalTransfer, bool, error) {
+	if !movesValue(f.Type) {
+		return model.InternalTransfer{}, false, nil
+	}
+
+	value, err := parseOptionalHexBig(f.Value)
+	if err != nil || value == nil || value.Sign() == 0 {
+		return model.InternalTransfer{}, false, err
+	}
+
+	return model.InternalTransfer{
+		Type:  strings.ToLower(f.Type),
+		From:  normalizeAddress(f.From),
+		To:    normalizeAddress(f.To),
+		Value: value,
+	}, true, nil
+}
+
+// Trace is one entry of the flat call list returned by trace_block.
+type Trace struct {
+	Type                string      `json:"type"`
+	Action              TraceAction `json:"action"`
+	Result              TraceResult `json:"result"`
+	Error               string      `json:"error,omitempty"`
+	TraceAddress        []int       `json:"traceAddress"`
+	TransactionHash     string      `json:"transactionHash"`
+	TransactionPosition *uint64     `json:"transactionPosition"`
+}
+
+// TraceAction describes what a trace_block call did. Which fields are set
+// depends on the trace type.
+type TraceAction struct {
+	CallType      string        `json:"callType,omitempty"`
+	From          model.Address `json:"from,omitempty"`
+	To            model.Address `json:"to,omitempty"`
+	Value         string        `json:"value,omitempty"`
+	Address       model.Address `json:"address,omitempty"`
+	RefundAddress model.Address `json:"refundAddress,omitempty"`
+	Balance       string        `json:"balance,omitempty"`
+}
+
+// TraceResult is the outcome of a trace_block call. Address is set for creations.
+type TraceResult struct {
+	Address model.Address `json:"address,omitempty"`
+}
+
+// toModel converts a trace that moved value into an internal transfer. It
+// reports false for traces that moved none.
+func (t Trace) toModel() (model.InternalTransfer, bool, error) {
+	var transfer model.InternalTransfer
+	var rawValue string
+
+	switch t.Type {
+	case "call":
+		transfer = model.InternalTransfer{Type: t.Action.CallType, From: t.Action.From, To: t.Action.To}
+		rawValue = t.Action.Value
+	case "create":
+		transfer = model.InternalTransfer{Type: t.Type, From: t.Action.From, To: t.Result.Address}
+		rawValue = t.Action.Value
+	case "suicide":
+		transfer = model.InternalTransfer{Type: "selfdestruct", From: t.Action.Address, To: t.Action.RefundAddress}
+		rawValue = t.Action.Balance
+	default:
+		return model.InternalTransfer{}, false, nil
+	}
+
+	if !movesValue(transfer.Type) {
+		return model.InternalTransfer{}, false, nil
+	}
+
+	value, err := parseOptionalHexBig(rawValue)
+	if err != nil || value == nil || value.Sign() == 0 {
+		return model.InternalTransfer{}, false, err
+	}
+
+	transfer.Type = strings.ToLower(transfer.Type)
+	transfer.From = normalizeAddress(transfer.From)
+	transfer.To = normalizeAddress(transfer.To)
+	transfer.Value = value
+
+	return transfer, true, nil
+}
+
+// movesValue reports whether a call of the given type can send ETH. Delegate
+// and static calls report the value of their caller's context, which they do
+// not move.
+func movesValue(callType string) bool {
+	switch strings.ToLower(callType) {
+	case "call", "create", "create2", "selfdestruct":
+		return true
+	default:
+		return false
+	}
+}
+
+// descendsFromAny reports whether the trace at address is at or below any of ancestors.
+func descendsFromAny(address []int, ancestors [][]int) bool {
+	for _, ancestor := range ancestors {
+		if len(address) >= len(ancestor) && slices.Equal(address[:len(ancestor)], ancestor) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,136 @@
+package ethereum_test
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"trustwallet/internal/clients/ethereum"
+	"trustwallet/internal/model"
+)
+
+func TestClient_GetInternalTransfers_Debug(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		bodyBytes, _ := io.ReadAll(r.Body)
+		assert.Contains(t, string(bodyBytes), `"method":"debug_traceBlockByNumber","params":["0x64",{"tracer":"callTracer"}]`)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, err := w.Write([]byte(`{
+            "jsonrpc": "2.0",
+            "id": 1,
+            "result": [{
+                "txHash": "0xTxHash1",
+                "result": {
+                    "type": "CALL",
+                    "from": "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+                    "to": "0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359",
+                    "value": "0x0",
+                    "calls": [
+                        {"type": "CALL", "from": "0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359", "to": "0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB", "value": "0xde0b6b3a7640000"},
+                        {"type": "DELEGATECALL", "from": "0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359", "to": "0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb", "value": "0x5"},
+                        {"type": "CALL", "from": "0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359", "to": "0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb", "value": "0x1", "error": "execution reverted", "calls": [
+                            {"type": "CALL", "from": "0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb", "to": "0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB", "value": "0x2"}
+                        ]},
+                        {"type": "STATICCALL", "from": "0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359", "to": "0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB"},
+                        {"type": "CALL", "from": "0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359", "to": "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", "value": "0x3"}
+                    ]
+                }
+            }]
+        }`))
+		assert.NoError(t, err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	client := ethereum.New(server.URL, server.Client())
+
+	transfers, err := client.GetInternalTransfers(context.Background(), 100, model.TraceAPIDebug)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.InternalTransfer{
+		{
+			TransactionHash: "0xTxHash1",
+			Index:           1,
+			Type:            "call",
+			From:            "0xfb6916095ca1df60bb79ce92ce3ea74c37c5d359",
+			To:              "0xdbf03b407c01e7cd3cbea99509d93f8dddc8c6fb",
+			Value:           big.NewInt(1_000_000_000_000_000_000),
+		},
+		{
+			TransactionHash: "0xTxHash1",
+			Index:           6,
+			Type:            "call",
+			From:            "0xfb6916095ca1df60bb79ce92ce3ea74c37c5d359",
+			To:              "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed",
+			Value:           big.NewInt(3),
+		},
+	}, transfers)
+}
+
+func TestClient_GetInternalTransfers_Trace(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		bodyBytes, _ := io.ReadAll(r.Body)
+		assert.Contains(t, string(bodyBytes), `"method":"trace_block","params":["0x64"]`)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, err := w.Write([]byte(`{
+            "jsonrpc": "2.0",
+            "id": 1,
+            "result": [
+                {"type": "call", "action": {"callType": "call", "from": "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", "to": "0xfb6916095ca1df60bb79ce92ce3ea74c37c5d359", "value": "0x0"}, "traceAddress": [], "transactionHash": "0xTxHash1", "transactionPosition": 0},
+                {"type": "call", "action": {"callType": "call", "from": "0xfb6916095ca1df60bb79ce92ce3ea74c37c5d359", "to": "0xdbf03b407c01e7cd3cbea99509d93f8dddc8c6fb", "value": "0x2"}, "traceAddress": [0], "transactionHash": "0xTxHash1", "transactionPosition": 0},
+                {"type": "call", "action": {"callType": "call", "from": "0xfb6916095ca1df60bb79ce92ce3ea74c37c5d359", "to": "0xd1220a0cf47c7b9be7a2e6ba89f429762e7b9adb", "value": "0x1"}, "error": "Reverted", "traceAddress": [1], "transactionHash": "0xTxHash1", "transactionPosition": 0},
+                {"type": "call", "action": {"callType": "call", "from": "0xd1220a0cf47c7b9be7a2e6ba89f429762e7b9adb", "to": "0xdbf03b407c01e7cd3cbea99509d93f8dddc8c6fb", "value": "0x5"}, "traceAddress": [1, 0], "transactionHash": "0xTxHash1", "transactionPosition": 0},
+                {"type": "create", "action": {"from": "0xfb6916095ca1df60bb79ce92ce3ea74c37c5d359", "value": "0x7"}, "result": {"address": "0x06012c8cf97bead5deae237070f9587f8e7a266d"}, "traceAddress": [2], "transactionHash": "0xTxHash1", "transactionPosition": 0},
+                {"type": "suicide", "action": {"address": "0x06012c8cf97bead5deae237070f9587f8e7a266d", "refundAddress": "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", "balance": "0x9"}, "traceAddress": [2, 0], "transactionHash": "0xTxHash1", "transactionPosition": 0},
+                {"type": "call", "action": {"callType": "delegatecall", "from": "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", "to": "0xfb6916095ca1df60bb79ce92ce3ea74c37c5d359", "value": "0x4"}, "traceAddress": [0], "transactionHash": "0xTxHash2", "transactionPosition": 1},
+                {"type": "reward", "action": {"author": "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", "value": "0x1bc16d674ec80000"}, "traceAddress": [], "transactionHash": null, "transactionPosition": null}
+            ]
+        }`))
+		assert.NoError(t, err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	client := ethereum.New(server.URL, server.Client())
+
+	transfers, err := client.GetInternalTransfers(context.Background(), 100, model.TraceAPITrace)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.InternalTransfer{
+		{
+			TransactionHash: "0xTxHash1",
+			Index:           1,
+			Type:            "call",
+			From:            "0xfb6916095ca1df60bb79ce92ce3ea74c37c5d359",
+			To:              "0xdbf03b407c01e7cd3cbea99509d93f8dddc8c6fb",
+			Value:           big.NewInt(2),
+		},
+		{
+			TransactionHash: "0xTxHash1",
+			Index:           4,
+			Type:            "create",
+			From:            "0xfb6916095ca1df60bb79ce92ce3ea74c37c5d359",
+			To:              "0x06012c8cf97bead5deae237070f9587f8e7a266d",
+			Value:           big.NewInt(7),
+		},
+		{
+			TransactionHash: "0xTxHash1",
+			Index:           5,
+			Type:            "selfdestruct",
+			From:            "0x06012c8cf97bead5deae237070f9587f8e7a266d",
+			To:              "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed",
+			Value:           big.NewInt(9),
+		},
+	}, transfers)
+}
+
+func TestClient_GetInternalTransfers_UnsupportedAPI(t *testing.T) {
+	client := ethereum.New("http://localhost", http.DefaultClient)
+
+	_, err := client.GetInternalTransfers(context.Background(), 100, "parity")
+	assert.Error(t, err)
+}
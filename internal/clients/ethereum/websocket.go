@@ -0,0 +1,196 @@
+package ethereum
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/gorilla/websocket"
+	"log"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// maxReconnectDelay caps the exponential backoff between reconnect attempts.
+	maxReconnectDelay = 30 * time.Second
+
+	defaultPingInterval = 30 * time.Second
+	defaultReadTimeout  = time.Minute
+	pingWriteTimeout    = 10 * time.Second
+)
+
+// HeadSubscriber follows new chain heads through an eth_subscribe("newHeads")
+// WebSocket subscription. It reconnects and resubscribes with exponential
+// backoff whenever the connection drops or goes silent.
+type HeadSubscriber struct {
+	url            string
+	dialer         *websocket.Dialer
+	reconnectDelay time.Duration
+	pingInterval   time.Duration
+	readTimeout    time.Duration
+	connected      *atomic.Bool
+}
+
+type HeadSubscriberOption func(*HeadSubscriber)
+
+// WithKeepalive makes the subscriber ping the node every pingInterval and
+// reconnect when nothing, not even a pong, was read for readTimeout. The
+// defaults are 30 seconds and one minute.
+func WithKeepalive(pingInterval, readTimeout time.Duration) HeadSubscriberOption {
+	return func(s *HeadSubscriber) {
+		s.pingInterval = pingInterval
+		s.readTimeout = readTimeout
+	}
+}
+
+func NewHeadSubscriber(url string, dialer *websocket.Dialer, reconnectDelay time.Duration, opts ...HeadSubscriberOption) *HeadSubscriber {
+	if dialer == nil {
+		dialer = websocket.DefaultDialer
+	}
+
+	s := &HeadSubscriber{
+		url:            url,
+		dialer:         dialer,
+		reconnectDelay: reconnectDelay,
+		pingInterval:   defaultPingInterval,
+		readTimeout:    defaultReadTimeout,
+		connected:      &atomic.Bool{},
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Connected reports whether the subscription is currently live, so callers
+// can fall back to polling while it is not.
+func (s *HeadSubscriber) Connected() bool {
+	return s.connected.Load()
+}
+
+// Subscribe delivers the block number of every new head until ctx is done.
+// If the consumer falls behind only the latest head is kept.
+func (s *HeadSubscriber) Subscribe(ctx context.Context) <-chan uint64 {
+	heads := make(chan uint64, 1)
+
+	go func() {
+		defer close(heads)
+
+		delay := s.reconnectDelay
+		for {
+			err := s.follow(ctx, heads, func() { delay = s.reconnectDelay })
+			if ctx.Err() != nil {
+				return
+			}
+
+			log.Println("Head subscription lost, reconnecting in", delay, err)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(delay):
+			}
+
+			delay = min(delay*2, maxReconnectDelay)
+		}
+	}()
+
+	return heads
+}
+
+// follow runs one connection: it subscribes to newHeads and forwards heads
+// until the connection fails or nothing was read for the read timeout.
+// subscribed is called once the node confirmed the subscription.
+func (s *HeadSubscriber) follow(ctx context.Context, heads chan uint64, subscribed func()) error {
+	conn, _, err := s.dialer.DialContext(ctx, s.url, nil)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	extendDeadline := func() error {
+		return conn.SetReadDeadline(time.Now().Add(s.readTimeout))
+	}
+	if err := extendDeadline(); err != nil {
+		return err
+	}
+	conn.SetPongHandler(func(string) error { return extendDeadline() })
+
+	done := make(chan struct{})
+	defer close(done)
+	go s.ping(conn, done)
+
+	request := Request{JSONRPC: "2.0", ID: 1, Method: "eth_subscribe", Params: []interface{}{"newHeads"}}
+	if err := conn.WriteJSON(request); err != nil {
+		return err
+	}
+
+	var response Response
+	if err := conn.ReadJSON(&response); err != nil {
+		return err
+	}
+	if response.Error != nil {
+		return rpcError(response.Error)
+	}
+
+	var subscriptionID string
+	if err := json.Unmarshal(response.Result, &subscriptionID); err != nil {
+		return err
+	}
+
+	s.connected.Store(true)
+	defer s.connected.Store(false)
+	subscribed()
+
+	for {
+		var notification Notification
+		if err := conn.ReadJSON(&notification); err != nil {
+			return err
+		}
+		if err := extendDeadline(); err != nil {
+			return err
+		}
+
+		if notification.Method != "eth_subscription" || notification.Params.Subscription != subscriptionID {
+			continue
+		}
+
+		var header BlockHeader
+		if err := json.Unmarshal(notification.Params.Result, &header); err != nil {
+			return fmt.Errorf("decoding head: %w", err)
+		}
+
+		number, err := parseHexUint64(header.Number)
+		if err != nil {
+			return fmt.Errorf("decoding head: %w", err)
+		}
+
+		// Replace an unconsumed head instead of blocking the read loop.
+		select {
+		case <-heads:
+		default:
+		}
+		heads <- number
+	}
+}
+
+// ping pings the node every ping interval until done is closed. A failed ping
+// is left to the read deadline, which then ends the connection.
+func (s *HeadSubscriber) ping(conn *websocket.Conn, done <-chan struct{}) {
+	ticker := time.NewTicker(s.pingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			_ = conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(pingWriteTimeout))
+		}
+	}
+}
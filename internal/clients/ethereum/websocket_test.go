@@ -0,0 +1,203 @@
+package ethereum_test
+
+import (
+	"context"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+	"trustwallet/internal/clients/ethereum"
+)
+
+// newHeadsServer is an in-process stand-in for a node's WebSocket endpoint. Each
+// connection gets the heads returned by heads(connection), after which the
+// server drops the connection.
+func newHeadsServer(t *testing.T, heads func(connection int32) []string) (*httptest.Server, *atomic.Int32) {
+	upgrader := websocket.Upgrader{}
+	connections := &atomic.Int32{}
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer conn.Close()
+
+		connection := connections.Add(1)
+
+		var request ethereum.Request
+		if !assert.NoError(t, conn.ReadJSON(&request)) {
+			return
+		}
+		assert.Equal(t, "eth_subscribe", request.Method)
+		assert.Equal(t, []interface{}{"newHeads"}, request.Params)
+
+		assert.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(`{"jsonrpc":"2.0","id":1,"result":"0xSubscription"}`)))
+
+		for _, head := range heads(connection) {
+			notification := `{"jsonrpc":"2.0","method":"eth_subscription","params":{"subscription":"0xSubscription","result":{"number":"` + head + `","hash":"0xHash"}}}`
+			assert.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(notification)))
+		}
+
+		if connection > 1 {
+			// Keep the second connection open until the client goes away.
+			_, _, _ = conn.ReadMessage()
+		}
+	}
+
+	return httptest.NewServer(http.HandlerFunc(handler)), connections
+}
+
+func TestHeadSubscriber_Subscribe(t *testing.T) {
+	server, _ := newHeadsServer(t, func(connection int32) []string {
+		return []string{"0x10"}
+	})
+	defer server.Close()
+
+	subscriber := ethereum.NewHeadSubscriber("ws"+strings.TrimPrefix(server.URL, "http"), nil, time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	heads := subscriber.Subscribe(ctx)
+
+	select {
+	case head := <-heads:
+		assert.Equal(t, uint64(16), head)
+	case <-time.After(time.Second):
+		t.Fatal("no head received")
+	}
+}
+
+func TestHeadSubscriber_Reconnect(t *testing.T) {
+	server, connections := newHeadsServer(t, func(connection int32) []string {
+		if connection == 1 {
+			return []string{"0x1"}
+		}
+		return []string{"0x2"}
+	})
+	defer server.Close()
+
+	subscriber := ethereum.NewHeadSubscriber("ws"+strings.TrimPrefix(server.URL, "http"), nil, time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	heads := subscriber.Subscribe(ctx)
+
+	deadline := time.After(time.Second)
+	for head := uint64(0); head != 2; {
+		select {
+		case head = <-heads:
+		case <-deadline:
+			t.Fatal("no head received after reconnect")
+		}
+	}
+
+	assert.Equal(t, int32(2), connections.Load(), "subscriber should reconnect and resubscribe")
+	assert.Eventually(t, subscriber.Connected, time.Second, time.Millisecond)
+
+	cancel()
+
+	for range heads {
+	}
+	assert.False(t, subscriber.Connected(), "subscriber should disconnect when the context is cancelled")
+}
+
+func TestHeadSubscriber_Unreachable(t *testing.T) {
+	subscriber := ethereum.NewHeadSubscriber("ws://127.0.0.1:1", nil, time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	heads := subscriber.Subscribe(ctx)
+
+	for range heads {
+		t.Fatal("unexpected head")
+	}
+	assert.False(t, subscriber.Connected())
+}
+
+// newQuietServer confirms the subscription and then sends no heads. Connections
+// for which answer returns false also stop reading, so pings go unanswered.
+func newQuietServer(t *testing.T, answer func(connection int32) bool) (*httptest.Server, *atomic.Int32) {
+	upgrader := websocket.Upgrader{}
+	connections := &atomic.Int32{}
+	release := make(chan struct{})
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer conn.Close()
+
+		connection := connections.Add(1)
+
+		var request ethereum.Request
+		if !assert.NoError(t, conn.ReadJSON(&request)) {
+			return
+		}
+		assert.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(`{"jsonrpc":"2.0","id":1,"result":"0xSubscription"}`)))
+
+		if !answer(connection) {
+			<-release
+			return
+		}
+
+		// Reading answers the client's pings until it goes away.
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	t.Cleanup(func() {
+		close(release)
+		server.Close()
+	})
+
+	return server, connections
+}
+
+func TestHeadSubscriber_ReconnectsWhenSilent(t *testing.T) {
+	server, connections := newQuietServer(t, func(connection int32) bool {
+		return connection > 1
+	})
+
+	subscriber := ethereum.NewHeadSubscriber("ws"+strings.TrimPrefix(server.URL, "http"), nil, time.Millisecond,
+		ethereum.WithKeepalive(10*time.Millisecond, 50*time.Millisecond))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	subscriber.Subscribe(ctx)
+
+	assert.Eventually(t, func() bool {
+		return connections.Load() == 2
+	}, time.Second, time.Millisecond, "subscriber should reconnect when the node stops answering pings")
+}
+
+func TestHeadSubscriber_KeepsQuietConnection(t *testing.T) {
+	server, connections := newQuietServer(t, func(connection int32) bool {
+		return true
+	})
+
+	subscriber := ethereum.NewHeadSubscriber("ws"+strings.TrimPrefix(server.URL, "http"), nil, time.Millisecond,
+		ethereum.WithKeepalive(10*time.Millisecond, 50*time.Millisecond))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	subscriber.Subscribe(ctx)
+
+	assert.Eventually(t, subscriber.Connected, time.Second, time.Millisecond)
+	time.Sleep(200 * time.Millisecond)
+
+	assert.True(t, subscriber.Connected())
+	assert.Equal(t, int32(1), connections.Load(), "answered pings should keep the connection")
+}
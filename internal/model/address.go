@@ -0,0 +1,91 @@
+package model
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/sha3"
+	"strings"
+)
+
+var (
+	ErrInvalidAddress  = errors.New("invalid address")
+	ErrInvalidChecksum = errors.New("invalid address checksum")
+)
+
+// Address is an account address in its canonical form: "0x" followed by 40
+// lowercase hex digits. Use ParseAddress to turn user input into an Address.
+type Address string
+
+// ParseAddress validates an address and returns it in canonical form. A
+// mixed-case address must carry a valid EIP-55 checksum; all-lowercase and
+// all-uppercase addresses carry none and are accepted as they are.
+func ParseAddress(s string) (Address, error) {
+	if len(s) != 42 || (s[:2] != "0x" && s[:2] != "0X") {
+		return "", fmt.Errorf("%w %q: want 0x followed by 40 hex digits", ErrInvalidAddress, s)
+	}
+
+	digits := s[2:]
+	if _, err := hex.DecodeString(digits); err != nil {
+		return "", fmt.Errorf("%w %q: want 0x followed by 40 hex digits", ErrInvalidAddress, s)
+	}
+
+	address := Address("0x" + strings.ToLower(digits))
+
+	if digits != strings.ToLower(digits) && digits != strings.ToUpper(digits) && address.Checksum() != "0x"+digits {
+		return "", fmt.Errorf("%w %q", ErrInvalidChecksum, s)
+	}
+
+	return address, nil
+}
+
+// CreateAddress computes the address of the contract that sender deploys with
+// a creation transaction of the given nonce: the last 20 bytes of the
+// Keccak-256 hash of the RLP encoding of [sender, nonce].
+func CreateAddress(sender Address, nonce uint64) Address {
+	senderBytes, _ := hex.DecodeString(strings.TrimPrefix(string(sender), "0x"))
+
+	var nonceBytes []byte
+	for n := nonce; n > 0; n >>= 8 {
+		nonceBytes = append([]byte{byte(n)}, nonceBytes...)
+	}
+
+	payload := append([]byte{0x80 + byte(len(senderBytes))}, senderBytes...)
+	switch {
+	case nonce == 0:
+		payload = append(payload, 0x80)
+	case nonce < 0x80:
+		payload = append(payload, byte(nonce))
+	default:
+		payload = append(payload, 0x80+byte(len(nonceBytes)))
+		payload = append(payload, nonceBytes...)
+	}
+
+	hash := sha3.NewLegacyKeccak256()
+	hash.Write(append([]byte{0xc0 + byte(len(payload))}, payload...))
+
+	return Address("0x" + hex.EncodeToString(hash.Sum(nil)[12:]))
+}
+
+// Checksum renders the address in its EIP-55 mixed-case checksum form.
+func (a Address) Checksum() string {
+	digits := strings.ToLower(strings.TrimPrefix(string(a), "0x"))
+
+	hash := sha3.NewLegacyKeccak256()
+	hash.Write([]byte(digits))
+	sum := hash.Sum(nil)
+
+	checksummed := []byte(digits)
+	for i, c := range checksummed {
+		nibble := sum[i/2] >> 4
+		if i%2 == 1 {
+			nibble = sum[i/2] & 0x0f
+		}
+
+		if c >= 'a' && c <= 'f' && nibble >= 8 {
+			checksummed[i] = c - 'a' + 'A'
+		}
+	}
+
+	return "0x" + string(checksummed)
+}
@@ -0,0 +1,64 @@
+package model_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"trustwallet/internal/model"
+)
+
+func TestParseAddress(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    model.Address
+		wantErr error
+	}{
+		{"checksummed", "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", nil},
+		{"lowercase", "0xfb6916095ca1df60bb79ce92ce3ea74c37c5d359", "0xfb6916095ca1df60bb79ce92ce3ea74c37c5d359", nil},
+		{"uppercase", "0xFB6916095CA1DF60BB79CE92CE3EA74C37C5D359", "0xfb6916095ca1df60bb79ce92ce3ea74c37c5d359", nil},
+		{"bad checksum", "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD", "", model.ErrInvalidChecksum},
+		{"too short", "0x5aaeb6053f3e94c9b9a09f33669435e7ef1bea", "", model.ErrInvalidAddress},
+		{"no prefix", "5aaeb6053f3e94c9b9a09f33669435e7ef1beaed00", "", model.ErrInvalidAddress},
+		{"not hex", "0xYourEthereumAddress00000000000000000000", "", model.ErrInvalidAddress},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := model.ParseAddress(tt.input)
+			assert.ErrorIs(t, err, tt.wantErr)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestAddress_Checksum(t *testing.T) {
+	// Test vectors from EIP-55.
+	for _, want := range []string{
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		"0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359",
+		"0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB",
+		"0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb",
+	} {
+		address, err := model.ParseAddress(want)
+		assert.NoError(t, err)
+		assert.Equal(t, want, address.Checksum())
+	}
+}
+
+func TestCreateAddress(t *testing.T) {
+	sender := model.Address("0x6ac7ea33f8831ea9dcc53393aaa88b25a785dbf0")
+
+	tests := []struct {
+		nonce uint64
+		want  model.Address
+	}{
+		{0, "0xcd234a471b72ba2f1ccf0a70fcaba648a5eecd8d"},
+		{1, "0x343c43a37d37dff08ae8c4a11544c718abb4fcf8"},
+		{2, "0xf778b86fa74e846c4f0a1fbd1335fe81c00a0c91"},
+		{3, "0xfffd933a0bc612844eaf0c6fe3e5b8e9b6c1d19c"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, model.CreateAddress(sender, tt.nonce), "nonce %d", tt.nonce)
+	}
+}
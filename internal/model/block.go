@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+type Block struct {
+	Number       uint64
+	Hash         string
+	ParentHash   string
+	Timestamp    time.Time
+	Transactions []Transaction
+
+	// LogsBloom is the bloom filter over the block's logs, or nil if the node
+	// did not report one.
+	LogsBloom *Bloom
+
+	// Logs holds the block's event logs the parser asked for. It is only
+	// filled when token transfers are tracked.
+	Logs []Log
+
+	// InternalTransfers holds the ETH transfers made by contract calls within
+	// the block. It is only filled when internal transfers are tracked.
+	InternalTransfers []InternalTransfer
+}
+
+// BlockTag names a block by its position relative to the chain head.
+type BlockTag string
+
+const (
+	BlockTagLatest    BlockTag = "latest"
+	BlockTagSafe      BlockTag = "safe"
+	BlockTagFinalized BlockTag = "finalized"
+)
@@ -0,0 +1,73 @@
+package model
+
+import (
+	"encoding/hex"
+	"fmt"
+	"golang.org/x/crypto/sha3"
+	"strings"
+)
+
+// BloomLength is the size in bytes of a block's logs bloom.
+const BloomLength = 256
+
+// Bloom is the 2048 bit bloom filter a block header carries over the
+// addresses and topics of all logs in the block. A negative test is
+// definitive; a positive one may be a false positive.
+type Bloom [BloomLength]byte
+
+// ParseBloom decodes a hex encoded logs bloom.
+func ParseBloom(s string) (Bloom, error) {
+	var bloom Bloom
+
+	raw, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil || len(raw) != BloomLength {
+		return bloom, fmt.Errorf("invalid logs bloom %q", s)
+	}
+
+	copy(bloom[:], raw)
+	return bloom, nil
+}
+
+// Add sets the bits of data in the bloom.
+func (b *Bloom) Add(data []byte) {
+	for _, bit := range bloomBits(data) {
+		b[BloomLength-1-bit/8] |= 1 << (bit % 8)
+	}
+}
+
+// Test reports whether data may be in the bloom.
+func (b *Bloom) Test(data []byte) bool {
+	for _, bit := range bloomBits(data) {
+		if b[BloomLength-1-bit/8]&(1<<(bit%8)) == 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// MayContainAddress reports whether a log in the bloom may have been emitted
+// by address or carry it as an indexed topic.
+func (b *Bloom) MayContainAddress(address Address) bool {
+	raw, err := hex.DecodeString(strings.TrimPrefix(string(address), "0x"))
+	if err != nil {
+		return true
+	}
+
+	return b.Test(raw) || b.Test(append(make([]byte, 12), raw...))
+}
+
+// bloomBits returns the three bit positions the Keccak-256 hash of data sets:
+// the low 11 bits of each of its first three 16 bit words.
+func bloomBits(data []byte) [3]uint {
+	hash := sha3.NewLegacyKeccak256()
+	hash.Write(data)
+	sum := hash.Sum(nil)
+
+	var bits [3]uint
+	for i := range bits {
+		bits[i] = (uint(sum[2*i])<<8 | uint(sum[2*i+1])) & 2047
+	}
+
+	return bits
+}
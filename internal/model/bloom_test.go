@@ -0,0 +1,57 @@
+package model_test
+
+import (
+	"encoding/hex"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"trustwallet/internal/model"
+)
+
+func TestBloom_Test(t *testing.T) {
+	var bloom model.Bloom
+	for _, data := range []string{"testtest", "test", "hallo", "other"} {
+		bloom.Add([]byte(data))
+	}
+
+	for _, data := range []string{"testtest", "test", "hallo", "other"} {
+		assert.True(t, bloom.Test([]byte(data)), data)
+	}
+
+	for _, data := range []string{"tes", "lo"} {
+		assert.False(t, bloom.Test([]byte(data)), data)
+	}
+}
+
+func TestBloom_MayContainAddress(t *testing.T) {
+	emitter := model.Address("0xdac17f958d2ee523a2206206994597c13d831ec7")
+	recipient := model.Address("0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed")
+	other := model.Address("0xfb6916095ca1df60bb79ce92ce3ea74c37c5d359")
+
+	emitterBytes, _ := hex.DecodeString(strings.TrimPrefix(string(emitter), "0x"))
+	recipientTopic, _ := hex.DecodeString(strings.Repeat("0", 24) + strings.TrimPrefix(string(recipient), "0x"))
+
+	var bloom model.Bloom
+	bloom.Add(emitterBytes)
+	bloom.Add(recipientTopic)
+
+	assert.True(t, bloom.MayContainAddress(emitter), "emitter")
+	assert.True(t, bloom.MayContainAddress(recipient), "indexed topic")
+	assert.False(t, bloom.MayContainAddress(other))
+
+	var empty model.Bloom
+	assert.False(t, empty.MayContainAddress(emitter))
+}
+
+func TestParseBloom(t *testing.T) {
+	var want model.Bloom
+	want[0] = 0x80
+	want[model.BloomLength-1] = 0x01
+
+	got, err := model.ParseBloom("0x80" + strings.Repeat("00", model.BloomLength-2) + "01")
+	assert.NoError(t, err)
+	assert.Equal(t, want, got)
+
+	_, err = model.ParseBloom("0x1234")
+	assert.Error(t, err)
+}
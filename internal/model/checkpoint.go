@@ -0,0 +1,30 @@
+package model
+
+// Checkpoint is the last block the parser has fully processed. Pending lists
+// the ingested blocks up to it that are not final yet, so a restarted parser
+// can still update the status of their activity or roll them back.
+type Checkpoint struct {
+	BlockNumber uint64         `json:"blockNumber"`
+	BlockHash   string         `json:"blockHash"`
+	Pending     []PendingBlock `json:"pending,omitempty"`
+}
+
+// PendingBlock is an ingested block that is not final yet, with the keys of
+// the activity stored for it.
+type PendingBlock struct {
+	Number            uint64            `json:"number"`
+	Hash              string            `json:"hash"`
+	ParentHash        string            `json:"parentHash"`
+	Status            TransactionStatus `json:"status"`
+	Transactions      []ActivityKey     `json:"transactions,omitempty"`
+	TokenTransfers    []ActivityKey     `json:"tokenTransfers,omitempty"`
+	InternalTransfers []ActivityKey     `json:"internalTransfers,omitempty"`
+}
+
+// ActivityKey identifies activity stored for an address: a transaction by its
+// hash, a token or internal transfer also by its log or call index.
+type ActivityKey struct {
+	Address Address `json:"address"`
+	Hash    string  `json:"hash"`
+	Index   uint64  `json:"index,omitempty"`
+}
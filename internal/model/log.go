@@ -0,0 +1,12 @@
+package model
+
+// Log is an event emitted by a contract while executing a transaction.
+// Topics and Data are hex encoded as returned by the node.
+type Log struct {
+	Address         Address
+	Topics          []string
+	Data            string
+	BlockNumber     uint64
+	TransactionHash string
+	LogIndex        uint64
+}
@@ -0,0 +1,77 @@
+package model
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// NumberFormat selects how numeric quantities are rendered in JSON.
+type NumberFormat int
+
+const (
+	// NumberFormatDecimal renders quantities as decimal strings, which survive
+	// consumers that parse JSON numbers into float64.
+	NumberFormatDecimal NumberFormat = iota
+	// NumberFormatHex renders quantities as JSON-RPC hex quantities, e.g. "0x2a".
+	NumberFormatHex
+)
+
+func (f NumberFormat) formatBig(value *big.Int) *string {
+	if value == nil {
+		return nil
+	}
+
+	var s string
+	if f == NumberFormatHex {
+		s = "0x" + value.Text(16)
+	} else {
+		s = value.String()
+	}
+
+	return &s
+}
+
+func (f NumberFormat) formatUint(value uint64) string {
+	if f == NumberFormatHex {
+		return "0x" + strconv.FormatUint(value, 16)
+	}
+
+	return strconv.FormatUint(value, 10)
+}
+
+// parseQuantity parses a quantity written either as a hex quantity or as a
+// decimal string. A missing quantity parses as nil.
+func parseQuantity(s *string) (*big.Int, error) {
+	if s == nil {
+		return nil, nil
+	}
+
+	base := 10
+	digits := *s
+	if strings.HasPrefix(digits, "0x") {
+		base, digits = 16, digits[2:]
+	}
+
+	value, ok := new(big.Int).SetString(digits, base)
+	if !ok || value.Sign() < 0 {
+		return nil, fmt.Errorf("invalid quantity %q", *s)
+	}
+
+	return value, nil
+}
+
+// parseUintQuantity is parseQuantity for quantities that fit a uint64. A
+// missing quantity parses as zero.
+func parseUintQuantity(s string) (uint64, error) {
+	if s == "" {
+		return 0, nil
+	}
+
+	if strings.HasPrefix(s, "0x") {
+		return strconv.ParseUint(s[2:], 16, 64)
+	}
+
+	return strconv.ParseUint(s, 10, 64)
+}
@@ -0,0 +1,220 @@
+package model
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// DefaultQueryLimit is the page size of a TransactionQuery without a limit.
+const DefaultQueryLimit = 100
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Direction filters transactions by which side of them an address is on.
+type Direction string
+
+const (
+	DirectionAny      Direction = ""
+	DirectionIncoming Direction = "incoming"
+	DirectionOutgoing Direction = "outgoing"
+	DirectionSelf     Direction = "self"
+)
+
+// SortOrder orders transactions by block number and position in the block.
+type SortOrder string
+
+const (
+	SortAscending  SortOrder = "asc"
+	SortDescending SortOrder = "desc"
+)
+
+// TransactionQuery selects a page of an address's transactions. Zero fields
+// do not filter: FromBlock and ToBlock bound the block range inclusively, a
+// ToBlock of zero meaning no upper bound.
+type TransactionQuery struct {
+	FromBlock uint64
+	ToBlock   uint64
+	Direction Direction
+	// MinValue keeps transactions moving at least this many wei.
+	MinValue *big.Int
+	// Order defaults to SortAscending.
+	Order SortOrder
+	// Limit defaults to DefaultQueryLimit.
+	Limit int
+	// Cursor is the NextCursor of the previous page, or empty for the first.
+	Cursor string
+}
+
+// TransactionPage is a page of query results. NextCursor is empty on the
+// last page.
+type TransactionPage struct {
+	Transactions []Transaction `json:"transactions"`
+	NextCursor   string        `json:"nextCursor,omitempty"`
+}
+
+// Apply runs the query over txs, the transactions stored for address, and
+// returns the requested page. txs is not modified.
+func (q TransactionQuery) Apply(address Address, txs []Transaction) (TransactionPage, error) {
+	sorted := slices.Clone(txs)
+	slices.SortStableFunc(sorted, func(a, b Transaction) int {
+		if q.Order == SortDescending {
+			return PositionOf(b).compare(a)
+		}
+		return PositionOf(a).compare(b)
+	})
+
+	return q.Scan(address, func() (Transaction, bool, error) {
+		if len(sorted) == 0 {
+			return Transaction{}, false, nil
+		}
+
+		tx := sorted[0]
+		sorted = sorted[1:]
+		return tx, true, nil
+	})
+}
+
+// Scan builds the page the query selects from the transactions of address
+// that next yields, which must come in the query's order until next reports
+// there are no more. It stops calling next once the page is full, so a
+// storage that keeps transactions sorted reads little more than the page.
+func (q TransactionQuery) Scan(address Address, next func() (Transaction, bool, error)) (TransactionPage, error) {
+	after, hasCursor, err := q.After()
+	if err != nil {
+		return TransactionPage{}, err
+	}
+
+	descending := q.Order == SortDescending
+
+	limit := q.Limit
+	if limit <= 0 {
+		limit = DefaultQueryLimit
+	}
+
+	page := TransactionPage{Transactions: []Transaction{}}
+	for {
+		tx, ok, err := next()
+		if err != nil {
+			return TransactionPage{}, err
+		}
+		if !ok {
+			return page, nil
+		}
+
+		if !q.matches(address, tx) {
+			continue
+		}
+
+		// Skip everything up to and including the cursor in the query's order.
+		if hasCursor {
+			if c := after.compare(tx); (!descending && c >= 0) || (descending && c <= 0) {
+				continue
+			}
+		}
+
+		if len(page.Transactions) == limit {
+			page.NextCursor = PositionOf(page.Transactions[limit-1]).String()
+			return page, nil
+		}
+
+		page.Transactions = append(page.Transactions, tx)
+	}
+}
+
+// After returns the position the query's cursor points at, and false for a
+// query without a cursor.
+func (q TransactionQuery) After() (TransactionPosition, bool, error) {
+	if q.Cursor == "" {
+		return TransactionPosition{}, false, nil
+	}
+
+	position, err := parseCursor(q.Cursor)
+	if err != nil {
+		return TransactionPosition{}, false, err
+	}
+
+	return position, true, nil
+}
+
+func (q TransactionQuery) matches(address Address, tx Transaction) bool {
+	if tx.BlockNumber < q.FromBlock || (q.ToBlock != 0 && tx.BlockNumber > q.ToBlock) {
+		return false
+	}
+
+	if q.MinValue != nil && (tx.Value == nil || tx.Value.Cmp(q.MinValue) < 0) {
+		return false
+	}
+
+	to := tx.To
+	if tx.IsContractCreation() {
+		to = tx.ContractAddress
+	}
+
+	switch q.Direction {
+	case DirectionIncoming:
+		return to == address && tx.From != address
+	case DirectionOutgoing:
+		return tx.From == address && to != address
+	case DirectionSelf:
+		return tx.From == address && to == address
+	default:
+		return true
+	}
+}
+
+// TransactionPosition is where a transaction sorts in ascending order: by
+// block number, then position in the block, then hash.
+type TransactionPosition struct {
+	BlockNumber      uint64
+	TransactionIndex uint64
+	Hash             string
+}
+
+// PositionOf returns where tx sorts.
+func PositionOf(tx Transaction) TransactionPosition {
+	return TransactionPosition{BlockNumber: tx.BlockNumber, TransactionIndex: tx.TransactionIndex, Hash: tx.Hash}
+}
+
+func parseCursor(s string) (TransactionPosition, error) {
+	parts := strings.SplitN(s, ":", 3)
+	if len(parts) != 3 {
+		return TransactionPosition{}, fmt.Errorf("%w %q", ErrInvalidCursor, s)
+	}
+
+	blockNumber, err := strconv.ParseUint(parts[0], 10, 64)
+	if err != nil {
+		return TransactionPosition{}, fmt.Errorf("%w %q", ErrInvalidCursor, s)
+	}
+
+	transactionIndex, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return TransactionPosition{}, fmt.Errorf("%w %q", ErrInvalidCursor, s)
+	}
+
+	return TransactionPosition{BlockNumber: blockNumber, TransactionIndex: transactionIndex, Hash: parts[2]}, nil
+}
+
+// String formats the position as a query cursor.
+func (p TransactionPosition) String() string {
+	return fmt.Sprintf("%d:%d:%s", p.BlockNumber, p.TransactionIndex, p.Hash)
+}
+
+// compare orders p against the position of tx in ascending order.
+func (p TransactionPosition) compare(tx Transaction) int {
+	other := PositionOf(tx)
+
+	if n := cmp.Compare(p.BlockNumber, other.BlockNumber); n != 0 {
+		return n
+	}
+
+	if n := cmp.Compare(p.TransactionIndex, other.TransactionIndex); n != 0 {
+		return n
+	}
+
+	return strings.Compare(p.Hash, other.Hash)
+}
@@ -0,0 +1,86 @@
+package model_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"testing"
+	"trustwallet/internal/model"
+)
+
+func TestTransactionQuery_Apply(t *testing.T) {
+	address := model.Address("0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed")
+	other := model.Address("0xfb6916095ca1df60bb79ce92ce3ea74c37c5d359")
+
+	incoming := model.Transaction{Hash: "0xIn", From: other, To: address, Value: big.NewInt(5), BlockNumber: 10, TransactionIndex: 1}
+	outgoing := model.Transaction{Hash: "0xOut", From: address, To: other, Value: big.NewInt(50), BlockNumber: 10, TransactionIndex: 0}
+	self := model.Transaction{Hash: "0xSelf", From: address, To: address, Value: big.NewInt(500), BlockNumber: 20}
+	creation := model.Transaction{Hash: "0xCreate", From: other, ContractAddress: address, Value: big.NewInt(0), BlockNumber: 30}
+	txs := []model.Transaction{self, incoming, creation, outgoing}
+
+	tests := []struct {
+		name  string
+		query model.TransactionQuery
+		want  []model.Transaction
+	}{
+		{"all ascending", model.TransactionQuery{}, []model.Transaction{outgoing, incoming, self, creation}},
+		{"all descending", model.TransactionQuery{Order: model.SortDescending}, []model.Transaction{creation, self, incoming, outgoing}},
+		{"block range", model.TransactionQuery{FromBlock: 11, ToBlock: 20}, []model.Transaction{self}},
+		{"from block", model.TransactionQuery{FromBlock: 20}, []model.Transaction{self, creation}},
+		{"incoming", model.TransactionQuery{Direction: model.DirectionIncoming}, []model.Transaction{incoming, creation}},
+		{"outgoing", model.TransactionQuery{Direction: model.DirectionOutgoing}, []model.Transaction{outgoing}},
+		{"self", model.TransactionQuery{Direction: model.DirectionSelf}, []model.Transaction{self}},
+		{"min value", model.TransactionQuery{MinValue: big.NewInt(50)}, []model.Transaction{outgoing, self}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := tt.query.Apply(address, txs)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, page.Transactions)
+			assert.Empty(t, page.NextCursor)
+		})
+	}
+}
+
+func TestTransactionQuery_Apply_Pagination(t *testing.T) {
+	address := model.Address("0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed")
+
+	var txs []model.Transaction
+	for i := uint64(0); i < 5; i++ {
+		txs = append(txs, model.Transaction{Hash: "0xTx" + string(rune('A'+i)), To: address, BlockNumber: 100 + i})
+	}
+
+	for _, order := range []model.SortOrder{model.SortAscending, model.SortDescending} {
+		t.Run(string(order), func(t *testing.T) {
+			query := model.TransactionQuery{Order: order, Limit: 2}
+
+			var got []model.Transaction
+			for pages := 0; ; pages++ {
+				if !assert.Less(t, pages, 3, "too many pages") {
+					return
+				}
+
+				page, err := query.Apply(address, txs)
+				assert.NoError(t, err)
+				assert.LessOrEqual(t, len(page.Transactions), 2)
+
+				got = append(got, page.Transactions...)
+				if page.NextCursor == "" {
+					break
+				}
+				query.Cursor = page.NextCursor
+			}
+
+			want := []model.Transaction{txs[0], txs[1], txs[2], txs[3], txs[4]}
+			if order == model.SortDescending {
+				want = []model.Transaction{txs[4], txs[3], txs[2], txs[1], txs[0]}
+			}
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+func TestTransactionQuery_Apply_InvalidCursor(t *testing.T) {
+	_, err := model.TransactionQuery{Cursor: "not a cursor"}.Apply("0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", nil)
+	assert.ErrorIs(t, err, model.ErrInvalidCursor)
+}
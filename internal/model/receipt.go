@@ -0,0 +1,82 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+)
+
+// ReceiptStatus tells whether a transaction's execution succeeded or reverted.
+type ReceiptStatus uint8
+
+const (
+	ReceiptStatusFailed  ReceiptStatus = 0
+	ReceiptStatusSuccess ReceiptStatus = 1
+)
+
+// Receipt holds what a node reports about a transaction after executing it.
+type Receipt struct {
+	TransactionHash   string
+	Status            ReceiptStatus
+	GasUsed           uint64
+	EffectiveGasPrice *big.Int
+	ContractAddress   Address
+}
+
+type receiptJSON struct {
+	TransactionHash   string        `json:"transactionHash"`
+	Status            ReceiptStatus `json:"status"`
+	GasUsed           string        `json:"gasUsed"`
+	EffectiveGasPrice *string       `json:"effectiveGasPrice,omitempty"`
+	ContractAddress   Address       `json:"contractAddress,omitempty"`
+}
+
+func (r Receipt) MarshalJSON() ([]byte, error) {
+	return json.Marshal(r.toJSON(NumberFormatDecimal))
+}
+
+// UnmarshalJSON accepts quantities in either the decimal or the hex form.
+func (r *Receipt) UnmarshalJSON(data []byte) error {
+	var raw receiptJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	receipt, err := raw.toReceipt()
+	if err != nil {
+		return err
+	}
+
+	*r = receipt
+	return nil
+}
+
+func (r Receipt) toJSON(format NumberFormat) receiptJSON {
+	return receiptJSON{
+		TransactionHash:   r.TransactionHash,
+		Status:            r.Status,
+		GasUsed:           format.formatUint(r.GasUsed),
+		EffectiveGasPrice: format.formatBig(r.EffectiveGasPrice),
+		ContractAddress:   r.ContractAddress,
+	}
+}
+
+func (r receiptJSON) toReceipt() (Receipt, error) {
+	gasUsed, err := parseUintQuantity(r.GasUsed)
+	if err != nil {
+		return Receipt{}, fmt.Errorf("gasUsed: %w", err)
+	}
+
+	effectiveGasPrice, err := parseQuantity(r.EffectiveGasPrice)
+	if err != nil {
+		return Receipt{}, fmt.Errorf("effectiveGasPrice: %w", err)
+	}
+
+	return Receipt{
+		TransactionHash:   r.TransactionHash,
+		Status:            r.Status,
+		GasUsed:           gasUsed,
+		EffectiveGasPrice: effectiveGasPrice,
+		ContractAddress:   r.ContractAddress,
+	}, nil
+}
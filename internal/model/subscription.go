@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// Subscription is an address the parser records activity for.
+type Subscription struct {
+	Address   Address   `json:"address"`
+	CreatedAt time.Time `json:"createdAt"`
+	Label     string    `json:"label,omitempty"`
+	// ExpiresAt is when the subscription stops matching new activity. Nil,
+	// like the zero time, means it never expires.
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+// Expired reports whether the subscription has expired at now.
+func (s Subscription) Expired(now time.Time) bool {
+	return s.ExpiresAt != nil && !s.ExpiresAt.IsZero() && !now.Before(*s.ExpiresAt)
+}
@@ -0,0 +1,54 @@
+package model_test
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+	"trustwallet/internal/model"
+)
+
+func TestSubscription_MarshalJSON(t *testing.T) {
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	data, err := json.Marshal(model.Subscription{Address: "0xAddress1", CreatedAt: createdAt})
+	assert.NoError(t, err)
+
+	var fields map[string]interface{}
+	assert.NoError(t, json.Unmarshal(data, &fields))
+	assert.NotContains(t, fields, "expiresAt", "a subscription without expiry should omit the field")
+
+	expiresAt := createdAt.Add(time.Hour)
+	data, err = json.Marshal(model.Subscription{Address: "0xAddress1", CreatedAt: createdAt, ExpiresAt: &expiresAt})
+	assert.NoError(t, err)
+
+	var decoded model.Subscription
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	if assert.NotNil(t, decoded.ExpiresAt) {
+		assert.True(t, decoded.ExpiresAt.Equal(expiresAt))
+	}
+}
+
+func TestSubscription_Expired(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	past, future, zero := now.Add(-time.Minute), now.Add(time.Minute), time.Time{}
+
+	tests := []struct {
+		name      string
+		expiresAt *time.Time
+		want      bool
+	}{
+		{name: "no expiry", expiresAt: nil, want: false},
+		{name: "zero time", expiresAt: &zero, want: false},
+		{name: "expired", expiresAt: &past, want: true},
+		{name: "expires now", expiresAt: &now, want: true},
+		{name: "not yet expired", expiresAt: &future, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subscription := model.Subscription{Address: "0xAddress1", ExpiresAt: tt.expiresAt}
+			assert.Equal(t, tt.want, subscription.Expired(now))
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+)
+
+// TokenStandard names the token interface a transfer event belongs to.
+type TokenStandard string
+
+const (
+	TokenStandardERC20   TokenStandard = "erc20"
+	TokenStandardERC721  TokenStandard = "erc721"
+	TokenStandardERC1155 TokenStandard = "erc1155"
+)
+
+// IsNFT reports whether the standard describes non-fungible tokens.
+func (s TokenStandard) IsNFT() bool {
+	return s == TokenStandardERC721 || s == TokenStandardERC1155
+}
+
+// TokenAmount is an amount of a single token id of an ERC-1155 contract.
+type TokenAmount struct {
+	TokenID *big.Int
+	Amount  *big.Int
+}
+
+// TokenTransfer is a token transfer decoded from a contract event log. A
+// transfer is identified by the transaction that emitted it and its log index.
+//
+// ERC-721 transfers carry the TokenID and an Amount of one. ERC-1155 transfers
+// carry the Operator and either a TokenID and Amount or, for TransferBatch
+// events, the moved amounts in Batch. Like Transaction, it marshals its
+// quantities as decimal strings; use Formatted for other forms.
+type TokenTransfer struct {
+	Standard        TokenStandard
+	Token           Address
+	Operator        Address
+	From            Address
+	To              Address
+	TokenID         *big.Int
+	Amount          *big.Int
+	Batch           []TokenAmount
+	TransactionHash string
+	LogIndex        uint64
+	BlockNumber     uint64
+	Status          TransactionStatus
+}
+
+// Parties returns the distinct addresses a transfer moves tokens between.
+func (t TokenTransfer) Parties() []Address {
+	if t.From == t.To {
+		return []Address{t.From}
+	}
+
+	return []Address{t.From, t.To}
+}
+
+// FormattedTokenTransfer marshals a token transfer with its quantities in Format.
+type FormattedTokenTransfer struct {
+	TokenTransfer
+	Format NumberFormat
+}
+
+// Formatted returns the transfer wrapped to marshal its quantities in format.
+func (t TokenTransfer) Formatted(format NumberFormat) FormattedTokenTransfer {
+	return FormattedTokenTransfer{TokenTransfer: t, Format: format}
+}
+
+type tokenAmountJSON struct {
+	TokenID *string `json:"tokenId"`
+	Amount  *string `json:"amount"`
+}
+
+type tokenTransferJSON struct {
+	Standard        TokenStandard     `json:"standard"`
+	Token           Address           `json:"token"`
+	Operator        Address           `json:"operator,omitempty"`
+	From            Address           `json:"from"`
+	To              Address           `json:"to"`
+	TokenID         *string           `json:"tokenId,omitempty"`
+	Amount          *string           `json:"amount,omitempty"`
+	Batch           []tokenAmountJSON `json:"batch,omitempty"`
+	TransactionHash string            `json:"transactionHash"`
+	LogIndex        string            `json:"logIndex"`
+	BlockNumber     string            `json:"blockNumber"`
+	Status          TransactionStatus `json:"status,omitempty"`
+}
+
+func (a TokenAmount) MarshalJSON() ([]byte, error) {
+	return json.Marshal(a.toJSON(NumberFormatDecimal))
+}
+
+// UnmarshalJSON accepts quantities in either the decimal or the hex form.
+func (a *TokenAmount) UnmarshalJSON(data []byte) error {
+	var raw tokenAmountJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	amount, err := raw.toTokenAmount()
+	if err != nil {
+		return err
+	}
+
+	*a = amount
+	return nil
+}
+
+func (a TokenAmount) toJSON(format NumberFormat) tokenAmountJSON {
+	return tokenAmountJSON{
+		TokenID: format.formatBig(a.TokenID),
+		Amount:  format.formatBig(a.Amount),
+	}
+}
+
+func (a tokenAmountJSON) toTokenAmount() (TokenAmount, error) {
+	tokenID, err := parseQuantity(a.TokenID)
+	if err != nil {
+		return TokenAmount{}, fmt.Errorf("tokenId: %w", err)
+	}
+
+	amount, err := parseQuantity(a.Amount)
+	if err != nil {
+		return TokenAmount{}, fmt.Errorf("amount: %w", err)
+	}
+
+	return TokenAmount{TokenID: tokenID, Amount: amount}, nil
+}
+
+func (t TokenTransfer) MarshalJSON() ([]byte, error) {
+	return t.Formatted(NumberFormatDecimal).MarshalJSON()
+}
+
+func (t FormattedTokenTransfer) MarshalJSON() ([]byte, error) {
+	f := t.Format
+
+	var batch []tokenAmountJSON
+	for _, amount := range t.Batch {
+		batch = append(batch, amount.toJSON(f))
+	}
+
+	return json.Marshal(tokenTransferJSON{
+		Standard:        t.Standard,
+		Token:           t.Token,
+		Operator:        t.Operator,
+		From:            t.From,
+		To:              t.To,
+		TokenID:         f.formatBig(t.TokenID),
+		Amount:          f.formatBig(t.Amount),
+		Batch:           batch,
+		TransactionHash: t.TransactionHash,
+		LogIndex:        f.formatUint(t.LogIndex),
+		BlockNumber:     f.formatUint(t.BlockNumber),
+		Status:          t.Status,
+	})
+}
+
+// UnmarshalJSON accepts quantities in either the decimal or the hex form.
+func (t *TokenTransfer) UnmarshalJSON(data []byte) error {
+	var raw tokenTransferJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	transfer := TokenTransfer{
+		Standard:        raw.Standard,
+		Token:           raw.Token,
+		Operator:        raw.Operator,
+		From:            raw.From,
+		To:              raw.To,
+		TransactionHash: raw.TransactionHash,
+		Status:          raw.Status,
+	}
+
+	var err error
+	if transfer.TokenID, err = parseQuantity(raw.TokenID); err != nil {
+		return fmt.Errorf("tokenId: %w", err)
+	}
+
+	if transfer.Amount, err = parseQuantity(raw.Amount); err != nil {
+		return fmt.Errorf("amount: %w", err)
+	}
+
+	for i, amount := range raw.Batch {
+		decoded, err := amount.toTokenAmount()
+		if err != nil {
+			return fmt.Errorf("batch %d: %w", i, err)
+		}
+		transfer.Batch = append(transfer.Batch, decoded)
+	}
+
+	if transfer.LogIndex, err = parseUintQuantity(raw.LogIndex); err != nil {
+		return fmt.Errorf("logIndex: %w", err)
+	}
+
+	if transfer.BlockNumber, err = parseUintQuantity(raw.BlockNumber); err != nil {
+		return fmt.Errorf("blockNumber: %w", err)
+	}
+
+	*t = transfer
+	return nil
+}
@@ -0,0 +1,86 @@
+package model_test
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"testing"
+	"trustwallet/internal/model"
+)
+
+func testTokenTransfer() model.TokenTransfer {
+	amount, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+
+	return model.TokenTransfer{
+		Standard:        model.TokenStandardERC1155,
+		Token:           "0xToken",
+		Operator:        "0xOperator",
+		From:            "0xFrom",
+		To:              "0xTo",
+		Batch:           []model.TokenAmount{{TokenID: big.NewInt(7), Amount: amount}},
+		TransactionHash: "0xHash",
+		LogIndex:        3,
+		BlockNumber:     100,
+		Status:          model.TransactionStatusConfirmed,
+	}
+}
+
+func TestTokenTransfer_MarshalJSON(t *testing.T) {
+	tests := []struct {
+		name      string
+		format    model.NumberFormat
+		wantBatch []interface{}
+		want      map[string]interface{}
+	}{
+		{
+			name:      "decimal",
+			format:    model.NumberFormatDecimal,
+			wantBatch: []interface{}{map[string]interface{}{"tokenId": "7", "amount": "123456789012345678901234567890"}},
+			want:      map[string]interface{}{"logIndex": "3", "blockNumber": "100"},
+		},
+		{
+			name:      "hex",
+			format:    model.NumberFormatHex,
+			wantBatch: []interface{}{map[string]interface{}{"tokenId": "0x7", "amount": "0x18ee90ff6c373e0ee4e3f0ad2"}},
+			want:      map[string]interface{}{"logIndex": "0x3", "blockNumber": "0x64"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(testTokenTransfer().Formatted(tt.format))
+			assert.NoError(t, err)
+
+			var fields map[string]interface{}
+			assert.NoError(t, json.Unmarshal(data, &fields))
+			assert.Equal(t, tt.wantBatch, fields["batch"])
+			for key, want := range tt.want {
+				assert.Equal(t, want, fields[key], key)
+			}
+			assert.NotContains(t, fields, "amount")
+
+			var decoded model.TokenTransfer
+			assert.NoError(t, json.Unmarshal(data, &decoded))
+			assert.Equal(t, testTokenTransfer(), decoded)
+		})
+	}
+}
+
+func TestTokenTransfer_MarshalJSON_DefaultsToDecimal(t *testing.T) {
+	transfer := model.TokenTransfer{Standard: model.TokenStandardERC20, Amount: big.NewInt(1_000_000)}
+
+	plain, err := json.Marshal(transfer)
+	assert.NoError(t, err)
+
+	decimal, err := json.Marshal(transfer.Formatted(model.NumberFormatDecimal))
+	assert.NoError(t, err)
+
+	assert.JSONEq(t, string(decimal), string(plain))
+	assert.Contains(t, string(plain), `"amount":"1000000"`)
+}
+
+func TestTokenTransfer_UnmarshalJSON_InvalidQuantity(t *testing.T) {
+	var transfer model.TokenTransfer
+	err := json.Unmarshal([]byte(`{"standard": "erc721", "tokenId": "0xZZ"}`), &transfer)
+	assert.ErrorContains(t, err, "tokenId")
+}
@@ -0,0 +1,126 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+)
+
+// TraceAPI names the node API used to trace the calls made by transactions.
+type TraceAPI string
+
+const (
+	// TraceAPIDebug uses debug_traceBlockByNumber with the callTracer, as
+	// served by Geth and compatible nodes.
+	TraceAPIDebug TraceAPI = "debug"
+	// TraceAPITrace uses trace_block, as served by Erigon and Nethermind.
+	TraceAPITrace TraceAPI = "trace"
+)
+
+// InternalTransfer is ETH moved by a call a contract made while executing a
+// transaction, rather than by the transaction itself. A transfer is identified
+// by its transaction and its Index, the position of its call frame in
+// depth-first order within the transaction's call tree. Like Transaction, it
+// marshals its quantities as decimal strings; use Formatted for other forms.
+type InternalTransfer struct {
+	TransactionHash  string
+	TransactionIndex uint64
+	Index            uint64
+	Type             string
+	From             Address
+	To               Address
+	Value            *big.Int
+	BlockNumber      uint64
+	Status           TransactionStatus
+}
+
+// Parties returns the distinct addresses an internal transfer moves ETH between.
+func (t InternalTransfer) Parties() []Address {
+	if t.From == t.To {
+		return []Address{t.From}
+	}
+
+	return []Address{t.From, t.To}
+}
+
+// FormattedInternalTransfer marshals an internal transfer with its quantities
+// in Format.
+type FormattedInternalTransfer struct {
+	InternalTransfer
+	Format NumberFormat
+}
+
+// Formatted returns the transfer wrapped to marshal its quantities in format.
+func (t InternalTransfer) Formatted(format NumberFormat) FormattedInternalTransfer {
+	return FormattedInternalTransfer{InternalTransfer: t, Format: format}
+}
+
+type internalTransferJSON struct {
+	TransactionHash  string            `json:"transactionHash"`
+	TransactionIndex string            `json:"transactionIndex"`
+	Index            string            `json:"index"`
+	Type             string            `json:"type"`
+	From             Address           `json:"from"`
+	To               Address           `json:"to"`
+	Value            *string           `json:"value"`
+	BlockNumber      string            `json:"blockNumber"`
+	Status           TransactionStatus `json:"status,omitempty"`
+}
+
+func (t InternalTransfer) MarshalJSON() ([]byte, error) {
+	return t.Formatted(NumberFormatDecimal).MarshalJSON()
+}
+
+func (t FormattedInternalTransfer) MarshalJSON() ([]byte, error) {
+	f := t.Format
+
+	return json.Marshal(internalTransferJSON{
+		TransactionHash:  t.TransactionHash,
+		TransactionIndex: f.formatUint(t.TransactionIndex),
+		Index:            f.formatUint(t.Index),
+		Type:             t.Type,
+		From:             t.From,
+		To:               t.To,
+		Value:            f.formatBig(t.Value),
+		BlockNumber:      f.formatUint(t.BlockNumber),
+		Status:           t.Status,
+	})
+}
+
+// UnmarshalJSON accepts quantities in either the decimal or the hex form.
+func (t *InternalTransfer) UnmarshalJSON(data []byte) error {
+	var raw internalTransferJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	transfer := InternalTransfer{
+		TransactionHash: raw.TransactionHash,
+		Type:            raw.Type,
+		From:            raw.From,
+		To:              raw.To,
+		Status:          raw.Status,
+	}
+
+	var err error
+	if transfer.Value, err = parseQuantity(raw.Value); err != nil {
+		return fmt.Errorf("value: %w", err)
+	}
+
+	for _, q := range []struct {
+		name  string
+		value string
+		into  *uint64
+	}{
+		{"transactionIndex", raw.TransactionIndex, &transfer.TransactionIndex},
+		{"index", raw.Index, &transfer.Index},
+		{"blockNumber", raw.BlockNumber, &transfer.BlockNumber},
+	} {
+		if *q.into, err = parseUintQuantity(q.value); err != nil {
+			return fmt.Errorf("%s: %w", q.name, err)
+		}
+	}
+
+	*t = transfer
+	return nil
+}
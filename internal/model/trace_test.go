@@ -0,0 +1,87 @@
+package model_test
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"testing"
+	"trustwallet/internal/model"
+)
+
+func testInternalTransfer() model.InternalTransfer {
+	value, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+
+	return model.InternalTransfer{
+		TransactionHash:  "0xHash",
+		TransactionIndex: 2,
+		Index:            1,
+		Type:             "call",
+		From:             "0xFrom",
+		To:               "0xTo",
+		Value:            value,
+		BlockNumber:      100,
+		Status:           model.TransactionStatusConfirmed,
+	}
+}
+
+func TestInternalTransfer_MarshalJSON(t *testing.T) {
+	tests := []struct {
+		name   string
+		format model.NumberFormat
+		want   map[string]interface{}
+	}{
+		{
+			name:   "decimal",
+			format: model.NumberFormatDecimal,
+			want: map[string]interface{}{
+				"value":            "123456789012345678901234567890",
+				"transactionIndex": "2",
+				"index":            "1",
+				"blockNumber":      "100",
+			},
+		},
+		{
+			name:   "hex",
+			format: model.NumberFormatHex,
+			want: map[string]interface{}{
+				"value":            "0x18ee90ff6c373e0ee4e3f0ad2",
+				"transactionIndex": "0x2",
+				"index":            "0x1",
+				"blockNumber":      "0x64",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(testInternalTransfer().Formatted(tt.format))
+			assert.NoError(t, err)
+
+			var fields map[string]interface{}
+			assert.NoError(t, json.Unmarshal(data, &fields))
+			for key, want := range tt.want {
+				assert.Equal(t, want, fields[key], key)
+			}
+
+			var decoded model.InternalTransfer
+			assert.NoError(t, json.Unmarshal(data, &decoded))
+			assert.Equal(t, testInternalTransfer(), decoded)
+		})
+	}
+}
+
+func TestInternalTransfer_MarshalJSON_DefaultsToDecimal(t *testing.T) {
+	plain, err := json.Marshal(testInternalTransfer())
+	assert.NoError(t, err)
+
+	decimal, err := json.Marshal(testInternalTransfer().Formatted(model.NumberFormatDecimal))
+	assert.NoError(t, err)
+
+	assert.JSONEq(t, string(decimal), string(plain))
+}
+
+func TestInternalTransfer_UnmarshalJSON_InvalidQuantity(t *testing.T) {
+	var transfer model.InternalTransfer
+	err := json.Unmarshal([]byte(`{"transactionHash": "0xHash", "value": "0xZZ"}`), &transfer)
+	assert.ErrorContains(t, err, "value")
+}
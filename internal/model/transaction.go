@@ -1,11 +1,208 @@
 package model
 
-type Address string
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"time"
+)
 
+// TransactionStatus tells how settled the block containing a transaction is.
+type TransactionStatus string
+
+const (
+	TransactionStatusPending   TransactionStatus = "pending"
+	TransactionStatusConfirmed TransactionStatus = "confirmed"
+	TransactionStatusFinalized TransactionStatus = "finalized"
+)
+
+// Transaction types as defined by EIP-2718.
+const (
+	TransactionTypeLegacy     uint8 = 0
+	TransactionTypeAccessList uint8 = 1
+	TransactionTypeDynamicFee uint8 = 2
+	TransactionTypeBlob       uint8 = 3
+)
+
+// Transaction is a transaction of a subscribed address. Amounts are in wei.
+// It marshals its quantities as decimal strings; use Formatted for other forms.
 type Transaction struct {
-	Hash        string  `json:"hash"`
-	From        Address `json:"from"`
-	To          Address `json:"to"`
-	Value       string  `json:"value"`
-	BlockNumber string  `json:"blockNumber"`
+	Hash                 string
+	From                 Address
+	To                   Address
+	Value                *big.Int
+	BlockNumber          uint64
+	BlockHash            string
+	Timestamp            time.Time
+	TransactionIndex     uint64
+	Nonce                uint64
+	Gas                  uint64
+	GasPrice             *big.Int
+	MaxFeePerGas         *big.Int
+	MaxPriorityFeePerGas *big.Int
+	Input                string
+	Type                 uint8
+	ChainID              *big.Int
+	Status               TransactionStatus
+
+	// ContractAddress is the address of the contract a creation transaction
+	// deploys. It is empty for any other transaction.
+	ContractAddress Address
+
+	// Receipt is only set when the parser is configured to fetch receipts.
+	Receipt *Receipt
+}
+
+// IsContractCreation reports whether the transaction deploys a contract, i.e.
+// has no recipient.
+func (t Transaction) IsContractCreation() bool {
+	return t.To == ""
+}
+
+// Parties returns the distinct addresses a transaction involves: its sender,
+// and its recipient or, for a creation, the deployed contract.
+func (t Transaction) Parties() []Address {
+	counterparty := t.To
+	if t.IsContractCreation() {
+		counterparty = t.ContractAddress
+	}
+
+	if counterparty == "" || counterparty == t.From {
+		return []Address{t.From}
+	}
+
+	return []Address{t.From, counterparty}
+}
+
+// FormattedTransaction marshals a transaction with its quantities in Format.
+type FormattedTransaction struct {
+	Transaction
+	Format NumberFormat
+}
+
+// Formatted returns the transaction wrapped to marshal its quantities in format.
+func (t Transaction) Formatted(format NumberFormat) FormattedTransaction {
+	return FormattedTransaction{Transaction: t, Format: format}
+}
+
+type transactionJSON struct {
+	Hash                 string            `json:"hash"`
+	From                 Address           `json:"from"`
+	To                   Address           `json:"to"`
+	Value                *string           `json:"value"`
+	BlockNumber          string            `json:"blockNumber"`
+	BlockHash            string            `json:"blockHash,omitempty"`
+	Timestamp            time.Time         `json:"timestamp"`
+	TransactionIndex     string            `json:"transactionIndex"`
+	Nonce                string            `json:"nonce"`
+	Gas                  string            `json:"gas"`
+	GasPrice             *string           `json:"gasPrice,omitempty"`
+	MaxFeePerGas         *string           `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas *string           `json:"maxPriorityFeePerGas,omitempty"`
+	Input                string            `json:"input,omitempty"`
+	Type                 uint8             `json:"type"`
+	ChainID              *string           `json:"chainId,omitempty"`
+	Status               TransactionStatus `json:"status,omitempty"`
+	ContractAddress      Address           `json:"contractAddress,omitempty"`
+	Receipt              *receiptJSON      `json:"receipt,omitempty"`
+}
+
+func (t Transaction) MarshalJSON() ([]byte, error) {
+	return t.Formatted(NumberFormatDecimal).MarshalJSON()
+}
+
+func (t FormattedTransaction) MarshalJSON() ([]byte, error) {
+	f := t.Format
+
+	var receipt *receiptJSON
+	if t.Receipt != nil {
+		r := t.Receipt.toJSON(f)
+		receipt = &r
+	}
+
+	return json.Marshal(transactionJSON{
+		Hash:                 t.Hash,
+		From:                 t.From,
+		To:                   t.To,
+		Value:                f.formatBig(t.Value),
+		BlockNumber:          f.formatUint(t.BlockNumber),
+		BlockHash:            t.BlockHash,
+		Timestamp:            t.Timestamp,
+		TransactionIndex:     f.formatUint(t.TransactionIndex),
+		Nonce:                f.formatUint(t.Nonce),
+		Gas:                  f.formatUint(t.Gas),
+		GasPrice:             f.formatBig(t.GasPrice),
+		MaxFeePerGas:         f.formatBig(t.MaxFeePerGas),
+		MaxPriorityFeePerGas: f.formatBig(t.MaxPriorityFeePerGas),
+		Input:                t.Input,
+		Type:                 t.Type,
+		ChainID:              f.formatBig(t.ChainID),
+		Status:               t.Status,
+		ContractAddress:      t.ContractAddress,
+		Receipt:              receipt,
+	})
+}
+
+// UnmarshalJSON accepts quantities in either the decimal or the hex form.
+func (t *Transaction) UnmarshalJSON(data []byte) error {
+	var raw transactionJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	tx := Transaction{
+		Hash:      raw.Hash,
+		From:      raw.From,
+		To:        raw.To,
+		BlockHash: raw.BlockHash,
+		Timestamp: raw.Timestamp,
+		Input:     raw.Input,
+		Type:      raw.Type,
+		Status:    raw.Status,
+
+		ContractAddress: raw.ContractAddress,
+	}
+
+	var err error
+	for _, q := range []struct {
+		name  string
+		value *string
+		into  **big.Int
+	}{
+		{"value", raw.Value, &tx.Value},
+		{"gasPrice", raw.GasPrice, &tx.GasPrice},
+		{"maxFeePerGas", raw.MaxFeePerGas, &tx.MaxFeePerGas},
+		{"maxPriorityFeePerGas", raw.MaxPriorityFeePerGas, &tx.MaxPriorityFeePerGas},
+		{"chainId", raw.ChainID, &tx.ChainID},
+	} {
+		if *q.into, err = parseQuantity(q.value); err != nil {
+			return fmt.Errorf("%s: %w", q.name, err)
+		}
+	}
+
+	for _, q := range []struct {
+		name  string
+		value string
+		into  *uint64
+	}{
+		{"blockNumber", raw.BlockNumber, &tx.BlockNumber},
+		{"transactionIndex", raw.TransactionIndex, &tx.TransactionIndex},
+		{"nonce", raw.Nonce, &tx.Nonce},
+		{"gas", raw.Gas, &tx.Gas},
+	} {
+		if *q.into, err = parseUintQuantity(q.value); err != nil {
+			return fmt.Errorf("%s: %w", q.name, err)
+		}
+	}
+
+	if raw.Receipt != nil {
+		receipt, err := raw.Receipt.toReceipt()
+		if err != nil {
+			return fmt.Errorf("receipt: %w", err)
+		}
+		tx.Receipt = &receipt
+	}
+
+	*t = tx
+	return nil
 }
@@ -0,0 +1,114 @@
+package model_test
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"testing"
+	"time"
+	"trustwallet/internal/model"
+)
+
+func testTransaction() model.Transaction {
+	value, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+
+	return model.Transaction{
+		Hash:        "0xHash",
+		From:        "0xFrom",
+		To:          "0xTo",
+		Value:       value,
+		BlockNumber: 100,
+		Timestamp:   time.Unix(1700000000, 0).UTC(),
+		Nonce:       42,
+		Gas:         21000,
+		GasPrice:    big.NewInt(20_000_000_000),
+		Type:        model.TransactionTypeLegacy,
+		Status:      model.TransactionStatusConfirmed,
+		Receipt: &model.Receipt{
+			TransactionHash: "0xHash",
+			Status:          model.ReceiptStatusSuccess,
+			GasUsed:         21000,
+		},
+	}
+}
+
+func TestTransaction_MarshalJSON(t *testing.T) {
+	tests := []struct {
+		name   string
+		format model.NumberFormat
+		want   map[string]interface{}
+	}{
+		{
+			name:   "decimal",
+			format: model.NumberFormatDecimal,
+			want: map[string]interface{}{
+				"value":       "123456789012345678901234567890",
+				"blockNumber": "100",
+				"nonce":       "42",
+				"gasPrice":    "20000000000",
+			},
+		},
+		{
+			name:   "hex",
+			format: model.NumberFormatHex,
+			want: map[string]interface{}{
+				"value":       "0x18ee90ff6c373e0ee4e3f0ad2",
+				"blockNumber": "0x64",
+				"nonce":       "0x2a",
+				"gasPrice":    "0x4a817c800",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(testTransaction().Formatted(tt.format))
+			assert.NoError(t, err)
+
+			var fields map[string]interface{}
+			assert.NoError(t, json.Unmarshal(data, &fields))
+			for key, want := range tt.want {
+				assert.Equal(t, want, fields[key], key)
+			}
+			assert.NotContains(t, fields, "maxFeePerGas")
+
+			var decoded model.Transaction
+			assert.NoError(t, json.Unmarshal(data, &decoded))
+			assert.Equal(t, testTransaction(), decoded)
+		})
+	}
+}
+
+func TestTransaction_MarshalJSON_DefaultsToDecimal(t *testing.T) {
+	plain, err := json.Marshal(testTransaction())
+	assert.NoError(t, err)
+
+	decimal, err := json.Marshal(testTransaction().Formatted(model.NumberFormatDecimal))
+	assert.NoError(t, err)
+
+	assert.JSONEq(t, string(decimal), string(plain))
+}
+
+func TestTransaction_UnmarshalJSON_InvalidQuantity(t *testing.T) {
+	var tx model.Transaction
+	err := json.Unmarshal([]byte(`{"hash": "0xHash", "value": "0xZZ", "blockNumber": "1"}`), &tx)
+	assert.ErrorContains(t, err, "value")
+}
+
+func TestTransaction_Parties(t *testing.T) {
+	tests := []struct {
+		name string
+		tx   model.Transaction
+		want []model.Address
+	}{
+		{"transfer", model.Transaction{From: "0xa", To: "0xb"}, []model.Address{"0xa", "0xb"}},
+		{"self transfer", model.Transaction{From: "0xa", To: "0xa"}, []model.Address{"0xa"}},
+		{"contract creation", model.Transaction{From: "0xa", ContractAddress: "0xc"}, []model.Address{"0xa", "0xc"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.tx.Parties())
+		})
+	}
+}
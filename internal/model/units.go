@@ -0,0 +1,44 @@
+package model
+
+import (
+	"math/big"
+	"strings"
+)
+
+const (
+	GweiDecimals  = 9
+	EtherDecimals = 18
+)
+
+// FormatEther renders an amount of wei in ether, e.g. "1.5", without losing precision.
+func FormatEther(wei *big.Int) string {
+	return formatUnits(wei, EtherDecimals)
+}
+
+// FormatGwei renders an amount of wei in gwei, e.g. "30.000000001", without losing precision.
+func FormatGwei(wei *big.Int) string {
+	return formatUnits(wei, GweiDecimals)
+}
+
+func formatUnits(wei *big.Int, decimals int) string {
+	if wei == nil {
+		return "0"
+	}
+
+	digits := new(big.Int).Abs(wei).String()
+	if len(digits) <= decimals {
+		digits = strings.Repeat("0", decimals-len(digits)+1) + digits
+	}
+
+	whole, fraction := digits[:len(digits)-decimals], strings.TrimRight(digits[len(digits)-decimals:], "0")
+
+	s := whole
+	if fraction != "" {
+		s += "." + fraction
+	}
+	if wei.Sign() < 0 {
+		s = "-" + s
+	}
+
+	return s
+}
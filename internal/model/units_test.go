@@ -0,0 +1,37 @@
+package model_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"testing"
+	"trustwallet/internal/model"
+)
+
+func TestFormatEther(t *testing.T) {
+	oneAndAHalf, _ := new(big.Int).SetString("1500000000000000000", 10)
+
+	tests := []struct {
+		name string
+		wei  *big.Int
+		want string
+	}{
+		{"nil", nil, "0"},
+		{"zero", big.NewInt(0), "0"},
+		{"one wei", big.NewInt(1), "0.000000000000000001"},
+		{"fraction", oneAndAHalf, "1.5"},
+		{"negative", new(big.Int).Neg(oneAndAHalf), "-1.5"},
+		{"whole", new(big.Int).Exp(big.NewInt(10), big.NewInt(21), nil), "1000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, model.FormatEther(tt.wei))
+		})
+	}
+}
+
+func TestFormatGwei(t *testing.T) {
+	assert.Equal(t, "30", model.FormatGwei(big.NewInt(30_000_000_000)))
+	assert.Equal(t, "30.000000001", model.FormatGwei(big.NewInt(30_000_000_001)))
+	assert.Equal(t, "0.5", model.FormatGwei(big.NewInt(500_000_000)))
+}
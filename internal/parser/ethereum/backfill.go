@@ -0,0 +1,211 @@
+package ethereum
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"trustwallet/internal/model"
+	"trustwallet/internal/storage"
+)
+
+// DefaultBackfillConcurrency is how many blocks a backfill fetches in parallel.
+const DefaultBackfillConcurrency = 4
+
+var (
+	ErrBackfillRunning  = errors.New("backfill already running for address")
+	ErrParserNotStarted = errors.New("parser has not started parsing yet")
+)
+
+// BackfillProgress reports how far a backfill for an address has got.
+type BackfillProgress struct {
+	FromBlock    uint64
+	ToBlock      uint64
+	CurrentBlock uint64
+	Found        int
+	Done         bool
+	Err          error
+}
+
+// Backfill scans the blocks from fromBlock up to the block the live tail has
+// reached for transactions of address, and merges them into storage block by
+// block without duplicating transactions that are already stored. It stops
+// with storage.ErrNotSubscribed once address is no longer subscribed. A
+// fromBlock of 0 scans from the start of the parser's window. The scan runs in
+// the background until it completes or ctx is cancelled; use
+// GetBackfillProgress to follow it.
+func (p *Parser) Backfill(ctx context.Context, rawAddress string, fromBlock uint64) error {
+	address, err := model.ParseAddress(rawAddress)
+	if err != nil {
+		return err
+	}
+
+	p.mu.RLock()
+	toBlock := p.currentBlock
+	windowStart := p.windowStart
+	p.mu.RUnlock()
+
+	if toBlock == 0 || windowStart == 0 {
+		return ErrParserNotStarted
+	}
+
+	if fromBlock == 0 {
+		fromBlock = windowStart
+	}
+
+	p.backfillMu.Lock()
+	if progress, ok := p.backfills[address]; ok && !progress.Done {
+		p.backfillMu.Unlock()
+		return ErrBackfillRunning
+	}
+	p.backfills[address] = BackfillProgress{
+		FromBlock:    fromBlock,
+		ToBlock:      toBlock,
+		CurrentBlock: fromBlock - 1,
+	}
+	p.backfillMu.Unlock()
+
+	go func() {
+		err := p.backfill(ctx, address, fromBlock, toBlock)
+		if err != nil {
+			log.Println("Backfill failed for address", address, err)
+		}
+
+		p.updateBackfillProgress(address, func(progress *BackfillProgress) {
+			progress.Done = true
+			progress.Err = err
+		})
+	}()
+
+	return nil
+}
+
+// GetBackfillProgress returns the progress of the latest backfill for address.
+func (p *Parser) GetBackfillProgress(rawAddress string) (BackfillProgress, bool) {
+	address, err := model.ParseAddress(rawAddress)
+	if err != nil {
+		return BackfillProgress{}, false
+	}
+
+	p.backfillMu.Lock()
+	defer p.backfillMu.Unlock()
+
+	progress, ok := p.backfills[address]
+	return progress, ok
+}
+
+func (p *Parser) backfill(ctx context.Context, address model.Address, fromBlock, toBlock uint64) error {
+	latestBlock, err := p.client.GetLatestBlockNumber(ctx)
+	if err != nil {
+		return err
+	}
+
+	finalizedBlock, err := p.client.GetBlockNumberByTag(ctx, model.BlockTagFinalized)
+	if err != nil {
+		return err
+	}
+
+	existing, err := p.storage.GetTransactions(ctx, address)
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool, len(existing))
+	for _, tx := range existing {
+		seen[tx.Hash] = true
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	involvesAddress := func(tx model.Transaction) (bool, error) {
+		return slices.Contains(tx.Parties(), address), nil
+	}
+
+	fetchTransactions := func(ctx context.Context, number uint64) ([]model.Transaction, error) {
+		txs, err := p.client.GetTransactionsByBlockNumber(ctx, number)
+		if err != nil {
+			return nil, err
+		}
+
+		return txs, p.attachReceipts(ctx, number, txs, involvesAddress)
+	}
+
+	for result := range fetchOrdered(ctx, fromBlock, toBlock, p.backfillConcurrency, p.fetchWindow, fetchTransactions) {
+		if result.err != nil {
+			return result.err
+		}
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		blockNumber := result.number
+
+		var txs []model.Transaction
+		for _, tx := range result.value {
+			if slices.Contains(tx.Parties(), address) && !seen[tx.Hash] {
+				txs = append(txs, tx)
+			}
+		}
+
+		if len(txs) > 0 {
+			if err := p.commitBackfilled(ctx, address, blockNumber, txs, latestBlock, finalizedBlock); err != nil {
+				return err
+			}
+		}
+
+		for _, tx := range txs {
+			seen[tx.Hash] = true
+		}
+
+		p.updateBackfillProgress(address, func(progress *BackfillProgress) {
+			progress.CurrentBlock = blockNumber
+			progress.Found += len(txs)
+		})
+	}
+
+	return nil
+}
+
+// commitBackfilled stores the transactions of address found in blockNumber as
+// one batch, the same way the live tail commits a block: only while address is
+// subscribed. A subscription that expired or was removed ends the backfill.
+func (p *Parser) commitBackfilled(ctx context.Context, address model.Address, blockNumber uint64, txs []model.Transaction, latestBlock, finalizedBlock uint64) error {
+	subscribed, err := p.storage.IsSubscribed(ctx, address)
+	if err != nil {
+		return fmt.Errorf("block %d: %w", blockNumber, err)
+	}
+	if !subscribed {
+		return storage.ErrNotSubscribed
+	}
+
+	var batch storage.Batch
+	for _, tx := range txs {
+		// Blocks the live tail still tracks get the transaction attached,
+		// so later status upgrades and reorg rollbacks cover it too.
+		status, tracked := p.history.attach(blockNumber, storedTransaction{address: address, hash: tx.Hash})
+		if !tracked {
+			status = p.statusOf(blockNumber, latestBlock, finalizedBlock)
+		}
+		tx.Status = status
+
+		batch.AddTransaction(address, tx)
+	}
+
+	if err := p.storage.CommitBatch(ctx, batch); err != nil {
+		return fmt.Errorf("commit block %d: %w", blockNumber, err)
+	}
+
+	return nil
+}
+
+func (p *Parser) updateBackfillProgress(address model.Address, update func(progress *BackfillProgress)) {
+	p.backfillMu.Lock()
+	defer p.backfillMu.Unlock()
+
+	progress := p.backfills[address]
+	update(&progress)
+	p.backfills[address] = progress
+}
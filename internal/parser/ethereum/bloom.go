@@ -0,0 +1,50 @@
+package ethereum
+
+import (
+	"context"
+	"fmt"
+	"trustwallet/internal/model"
+)
+
+// BloomStats counts the blocks the logs bloom pre-filter has looked at.
+type BloomStats struct {
+	// Checked is how many blocks had their bloom tested.
+	Checked int64
+	// Skipped is how many of those had no subscribed address in their bloom,
+	// so their logs were not fetched.
+	Skipped int64
+}
+
+// BloomStats returns how many blocks the logs bloom pre-filter has checked and
+// skipped. Both are zero unless WithBloomFilter is set.
+func (p *Parser) BloomStats() BloomStats {
+	return BloomStats{
+		Checked: p.bloomChecked.Load(),
+		Skipped: p.bloomSkipped.Load(),
+	}
+}
+
+// bloomMatches reports whether block may hold logs of a subscribed address,
+// either emitted by it or carrying it as an indexed topic. Blocks without a
+// bloom, and every block when the pre-filter is off, match.
+func (p *Parser) bloomMatches(ctx context.Context, block model.Block) (bool, error) {
+	if !p.bloomFilter || block.LogsBloom == nil {
+		return true, nil
+	}
+
+	addresses, err := p.storage.ListAddresses(ctx)
+	if err != nil {
+		return false, fmt.Errorf("subscribed addresses: %w", err)
+	}
+
+	p.bloomChecked.Add(1)
+
+	for _, address := range addresses {
+		if block.LogsBloom.MayContainAddress(address) {
+			return true, nil
+		}
+	}
+
+	p.bloomSkipped.Add(1)
+	return false, nil
+}
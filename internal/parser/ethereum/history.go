@@ -0,0 +1,214 @@
+package ethereum
+
+import (
+	"sort"
+	"sync"
+	"trustwallet/internal/model"
+)
+
+// storedTransaction identifies a transaction written to storage for a subscribed address.
+type storedTransaction struct {
+	address model.Address
+	hash    string
+}
+
+// storedTransfer identifies a token transfer written to storage for a subscribed address.
+type storedTransfer struct {
+	address  model.Address
+	hash     string
+	logIndex uint64
+}
+
+// storedInternalTransfer identifies an internal transfer written to storage for a subscribed address.
+type storedInternalTransfer struct {
+	address model.Address
+	hash    string
+	index   uint64
+}
+
+// blockRecord is what the parser remembers about an ingested block, so the
+// block can be undone if a reorg later orphans it.
+type blockRecord struct {
+	number            uint64
+	hash              string
+	parentHash        string
+	status            model.TransactionStatus
+	stored            []storedTransaction
+	transfers         []storedTransfer
+	internalTransfers []storedInternalTransfer
+}
+
+// pendingBlock converts the record to the form the checkpoint persists.
+func (r blockRecord) pendingBlock() model.PendingBlock {
+	block := model.PendingBlock{
+		Number:     r.number,
+		Hash:       r.hash,
+		ParentHash: r.parentHash,
+		Status:     r.status,
+	}
+
+	for _, tx := range r.stored {
+		block.Transactions = append(block.Transactions, model.ActivityKey{Address: tx.address, Hash: tx.hash})
+	}
+
+	for _, transfer := range r.transfers {
+		block.TokenTransfers = append(block.TokenTransfers, model.ActivityKey{Address: transfer.address, Hash: transfer.hash, Index: transfer.logIndex})
+	}
+
+	for _, transfer := range r.internalTransfers {
+		block.InternalTransfers = append(block.InternalTransfers, model.ActivityKey{Address: transfer.address, Hash: transfer.hash, Index: transfer.index})
+	}
+
+	return block
+}
+
+// recordOf restores the record of a block persisted by pendingBlock.
+func recordOf(block model.PendingBlock) blockRecord {
+	record := blockRecord{
+		number:     block.Number,
+		hash:       block.Hash,
+		parentHash: block.ParentHash,
+		status:     block.Status,
+	}
+
+	for _, key := range block.Transactions {
+		record.stored = append(record.stored, storedTransaction{address: key.Address, hash: key.Hash})
+	}
+
+	for _, key := range block.TokenTransfers {
+		record.transfers = append(record.transfers, storedTransfer{address: key.Address, hash: key.Hash, logIndex: key.Index})
+	}
+
+	for _, key := range block.InternalTransfers {
+		record.internalTransfers = append(record.internalTransfers, storedInternalTransfer{address: key.Address, hash: key.Hash, index: key.Index})
+	}
+
+	return record
+}
+
+// blockHistory keeps the records of the last depth ingested blocks, and of
+// older blocks until they are finalized. It is shared between the live tail
+// and backfills, so every method locks.
+type blockHistory struct {
+	mu      *sync.Mutex
+	depth   uint64
+	records map[uint64]blockRecord
+}
+
+func newBlockHistory(depth uint64) *blockHistory {
+	return &blockHistory{
+		mu:      &sync.Mutex{},
+		depth:   depth,
+		records: make(map[uint64]blockRecord),
+	}
+}
+
+func (h *blockHistory) add(record blockRecord) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.records[record.number] = record
+}
+
+// attach adds a transaction stored outside of the live tail to a tracked block
+// and returns the block's current status. It reports false if the block is not
+// tracked.
+func (h *blockHistory) attach(number uint64, tx storedTransaction) (model.TransactionStatus, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	record, ok := h.records[number]
+	if !ok {
+		return "", false
+	}
+
+	record.stored = append(record.stored, tx)
+	h.records[number] = record
+
+	return record.status, true
+}
+
+// setStatus updates the status of a tracked block and returns the updated record.
+func (h *blockHistory) setStatus(number uint64, status model.TransactionStatus) blockRecord {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	record, ok := h.records[number]
+	if !ok {
+		return blockRecord{}
+	}
+
+	record.status = status
+	h.records[number] = record
+
+	record.stored = append([]storedTransaction(nil), record.stored...)
+	record.transfers = append([]storedTransfer(nil), record.transfers...)
+	record.internalTransfers = append([]storedInternalTransfer(nil), record.internalTransfers...)
+
+	return record
+}
+
+// prune forgets finalized blocks more than depth blocks below head.
+func (h *blockHistory) prune(head uint64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for number, record := range h.records {
+		if number+h.depth <= head && record.status == model.TransactionStatusFinalized {
+			delete(h.records, number)
+		}
+	}
+}
+
+func (h *blockHistory) get(number uint64) (blockRecord, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	record, ok := h.records[number]
+	return record, ok
+}
+
+// above returns the records above number, newest first.
+func (h *blockHistory) above(number uint64) []blockRecord {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var records []blockRecord
+	for n, record := range h.records {
+		if n > number {
+			records = append(records, record)
+		}
+	}
+
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].number > records[j].number
+	})
+
+	return records
+}
+
+// truncate drops every record above number.
+func (h *blockHistory) truncate(number uint64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for n := range h.records {
+		if n > number {
+			delete(h.records, n)
+		}
+	}
+}
+
+func (h *blockHistory) unfinalized() []blockRecord {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var records []blockRecord
+	for _, record := range h.records {
+		if record.status != model.TransactionStatusFinalized {
+			records = append(records, record)
+		}
+	}
+
+	return records
+}
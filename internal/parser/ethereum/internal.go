@@ -0,0 +1,53 @@
+package ethereum
+
+import (
+	"context"
+	"fmt"
+	"trustwallet/internal/model"
+	"trustwallet/internal/storage"
+)
+
+// attachInternalTransfers traces block when internal transfers are tracked.
+func (p *Parser) attachInternalTransfers(ctx context.Context, block *model.Block) error {
+	if p.traceAPI == "" {
+		return nil
+	}
+
+	transfers, err := p.client.GetInternalTransfers(ctx, block.Number, p.traceAPI)
+	if err != nil {
+		return fmt.Errorf("traces of block %d: %w", block.Number, err)
+	}
+
+	block.InternalTransfers = transfers
+	return nil
+}
+
+// parseInternalTransfers adds the internal transfers of block to batch for
+// every subscribed party and returns what it added.
+func (p *Parser) parseInternalTransfers(ctx context.Context, block model.Block, status model.TransactionStatus, batch *storage.Batch) ([]storedInternalTransfer, error) {
+	var stored []storedInternalTransfer
+	for _, transfer := range block.InternalTransfers {
+		// Older nodes leave the transaction hash out of debug traces.
+		if transfer.TransactionHash == "" && transfer.TransactionIndex < uint64(len(block.Transactions)) {
+			transfer.TransactionHash = block.Transactions[transfer.TransactionIndex].Hash
+		}
+
+		transfer.BlockNumber = block.Number
+		transfer.Status = status
+
+		for _, party := range transfer.Parties() {
+			subscribed, err := p.storage.IsSubscribed(ctx, party)
+			if err != nil {
+				return nil, err
+			}
+			if !subscribed {
+				continue
+			}
+
+			batch.AddInternalTransfer(party, transfer)
+			stored = append(stored, storedInternalTransfer{address: party, hash: transfer.TransactionHash, index: transfer.Index})
+		}
+	}
+
+	return stored, nil
+}
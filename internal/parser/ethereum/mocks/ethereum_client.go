@@ -3,6 +3,8 @@
 package mocks
 
 import (
+	context "context"
+
 	model "trustwallet/internal/model"
 
 	mock "github.com/stretchr/testify/mock"
@@ -13,27 +15,203 @@ type EthereumClient struct {
 	mock.Mock
 }
 
-// GetLatestBlockNumber provides a mock function with given fields:
-func (_m *EthereumClient) GetLatestBlockNumber() (int64, error) {
-	ret := _m.Called()
+// GetBlockByNumber provides a mock function with given fields: ctx, blockNumber
+func (_m *EthereumClient) GetBlockByNumber(ctx context.Context, blockNumber uint64) (model.Block, error) {
+	ret := _m.Called(ctx, blockNumber)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBlockByNumber")
+	}
+
+	var r0 model.Block
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) (model.Block, error)); ok {
+		return rf(ctx, blockNumber)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) model.Block); ok {
+		r0 = rf(ctx, blockNumber)
+	} else {
+		r0 = ret.Get(0).(model.Block)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, blockNumber)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBlockNumberByTag provides a mock function with given fields: ctx, tag
+func (_m *EthereumClient) GetBlockNumberByTag(ctx context.Context, tag model.BlockTag) (uint64, error) {
+	ret := _m.Called(ctx, tag)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBlockNumberByTag")
+	}
+
+	var r0 uint64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.BlockTag) (uint64, error)); ok {
+		return rf(ctx, tag)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.BlockTag) uint64); ok {
+		r0 = rf(ctx, tag)
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.BlockTag) error); ok {
+		r1 = rf(ctx, tag)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBlockReceipts provides a mock function with given fields: ctx, blockNumber
+func (_m *EthereumClient) GetBlockReceipts(ctx context.Context, blockNumber uint64) ([]model.Receipt, error) {
+	ret := _m.Called(ctx, blockNumber)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBlockReceipts")
+	}
+
+	var r0 []model.Receipt
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) ([]model.Receipt, error)); ok {
+		return rf(ctx, blockNumber)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) []model.Receipt); ok {
+		r0 = rf(ctx, blockNumber)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Receipt)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, blockNumber)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBlocksByNumberRange provides a mock function with given fields: ctx, fromBlock, toBlock
+func (_m *EthereumClient) GetBlocksByNumberRange(ctx context.Context, fromBlock uint64, toBlock uint64) ([]model.Block, error) {
+	ret := _m.Called(ctx, fromBlock, toBlock)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBlocksByNumberRange")
+	}
+
+	var r0 []model.Block
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) ([]model.Block, error)); ok {
+		return rf(ctx, fromBlock, toBlock)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64) []model.Block); ok {
+		r0 = rf(ctx, fromBlock, toBlock)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Block)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64) error); ok {
+		r1 = rf(ctx, fromBlock, toBlock)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetInternalTransfers provides a mock function with given fields: ctx, blockNumber, api
+func (_m *EthereumClient) GetInternalTransfers(ctx context.Context, blockNumber uint64, api model.TraceAPI) ([]model.InternalTransfer, error) {
+	ret := _m.Called(ctx, blockNumber, api)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetInternalTransfers")
+	}
+
+	var r0 []model.InternalTransfer
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, model.TraceAPI) ([]model.InternalTransfer, error)); ok {
+		return rf(ctx, blockNumber, api)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, model.TraceAPI) []model.InternalTransfer); ok {
+		r0 = rf(ctx, blockNumber, api)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.InternalTransfer)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, model.TraceAPI) error); ok {
+		r1 = rf(ctx, blockNumber, api)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLatestBlockNumber provides a mock function with given fields: ctx
+func (_m *EthereumClient) GetLatestBlockNumber(ctx context.Context) (uint64, error) {
+	ret := _m.Called(ctx)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetLatestBlockNumber")
 	}
 
-	var r0 int64
+	var r0 uint64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) (uint64, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) uint64); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetLogs provides a mock function with given fields: ctx, blockNumber, topics
+func (_m *EthereumClient) GetLogs(ctx context.Context, blockNumber uint64, topics []string) ([]model.Log, error) {
+	ret := _m.Called(ctx, blockNumber, topics)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLogs")
+	}
+
+	var r0 []model.Log
 	var r1 error
-	if rf, ok := ret.Get(0).(func() (int64, error)); ok {
-		return rf()
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, []string) ([]model.Log, error)); ok {
+		return rf(ctx, blockNumber, topics)
 	}
-	if rf, ok := ret.Get(0).(func() int64); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, []string) []model.Log); ok {
+		r0 = rf(ctx, blockNumber, topics)
 	} else {
-		r0 = ret.Get(0).(int64)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Log)
+		}
 	}
 
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, []string) error); ok {
+		r1 = rf(ctx, blockNumber, topics)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -41,9 +219,9 @@ func (_m *EthereumClient) GetLatestBlockNumber() (int64, error) {
 	return r0, r1
 }
 
-// GetTransactionsByBlockNumber provides a mock function with given fields: blockNumber
-func (_m *EthereumClient) GetTransactionsByBlockNumber(blockNumber int64) ([]model.Transaction, error) {
-	ret := _m.Called(blockNumber)
+// GetTransactionsByBlockNumber provides a mock function with given fields: ctx, blockNumber
+func (_m *EthereumClient) GetTransactionsByBlockNumber(ctx context.Context, blockNumber uint64) ([]model.Transaction, error) {
+	ret := _m.Called(ctx, blockNumber)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetTransactionsByBlockNumber")
@@ -51,19 +229,19 @@ func (_m *EthereumClient) GetTransactionsByBlockNumber(blockNumber int64) ([]mod
 
 	var r0 []model.Transaction
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int64) ([]model.Transaction, error)); ok {
-		return rf(blockNumber)
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) ([]model.Transaction, error)); ok {
+		return rf(ctx, blockNumber)
 	}
-	if rf, ok := ret.Get(0).(func(int64) []model.Transaction); ok {
-		r0 = rf(blockNumber)
+	if rf, ok := ret.Get(0).(func(context.Context, uint64) []model.Transaction); ok {
+		r0 = rf(ctx, blockNumber)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]model.Transaction)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int64) error); ok {
-		r1 = rf(blockNumber)
+	if rf, ok := ret.Get(1).(func(context.Context, uint64) error); ok {
+		r1 = rf(ctx, blockNumber)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -0,0 +1,164 @@
+package ethereum
+
+import (
+	"time"
+	"trustwallet/internal/model"
+	"trustwallet/internal/storage"
+)
+
+type Option func(*Parser)
+
+// WithConfirmationDepth sets how many blocks must be built on top of a block
+// before its transactions are reported as confirmed rather than pending.
+func WithConfirmationDepth(depth uint64) Option {
+	return func(p *Parser) {
+		p.confirmationDepth = depth
+	}
+}
+
+// WithReorgDepth sets how many recent blocks the parser remembers to detect
+// and roll back reorgs, and how far it rewinds when a reorg reaches below them.
+func WithReorgDepth(depth uint64) Option {
+	return func(p *Parser) {
+		p.history = newBlockHistory(depth)
+	}
+}
+
+// WithFollowTag makes the parser ingest blocks only up to the given tag, e.g.
+// model.BlockTagSafe or model.BlockTagFinalized, instead of the unsafe head.
+func WithFollowTag(tag model.BlockTag) Option {
+	return func(p *Parser) {
+		p.followTag = tag
+	}
+}
+
+// WithCheckpointStore makes the parser save a checkpoint after every block and
+// resume from the stored checkpoint, if any, on its first StartParsing call.
+// A stored checkpoint takes precedence over the block passed to New.
+func WithCheckpointStore(store storage.CheckpointStore) Option {
+	return func(p *Parser) {
+		p.checkpoints = store
+	}
+}
+
+// WithWindowStart sets the first block the parser is responsible for. Backfills
+// requested without a start block scan from here. By default the window starts
+// at the first block the parser ingests.
+func WithWindowStart(blockNumber uint64) Option {
+	return func(p *Parser) {
+		p.windowStart = blockNumber
+	}
+}
+
+// WithFetchConcurrency sets how many blocks are fetched in parallel while the
+// parser catches up with the chain.
+func WithFetchConcurrency(concurrency int) Option {
+	return func(p *Parser) {
+		p.fetchConcurrency = concurrency
+	}
+}
+
+// WithFetchWindow bounds how many blocks may be fetched ahead of the last
+// block committed to storage.
+func WithFetchWindow(window int) Option {
+	return func(p *Parser) {
+		p.fetchWindow = window
+	}
+}
+
+// WithBatchSize makes the parser fetch up to size blocks per JSON-RPC batch
+// while catching up. Each batch counts as one fetch towards the concurrency
+// and window limits.
+func WithBatchSize(size int) Option {
+	return func(p *Parser) {
+		p.batchSize = size
+	}
+}
+
+// WithBackfillConcurrency sets how many blocks a backfill fetches in parallel.
+func WithBackfillConcurrency(concurrency int) Option {
+	return func(p *Parser) {
+		p.backfillConcurrency = concurrency
+	}
+}
+
+// WithReceipts makes the parser fetch the receipts of blocks holding
+// transactions it stores, so stored transactions carry their execution status,
+// gas used and created contract address.
+func WithReceipts() Option {
+	return func(p *Parser) {
+		p.receipts = true
+	}
+}
+
+// WithTokenTransfers makes the parser fetch the Transfer event logs of every
+// block and store the ERC-20 transfers of subscribed addresses. This costs one
+// extra request per block.
+func WithTokenTransfers() Option {
+	return func(p *Parser) {
+		p.tokenTransfers = true
+	}
+}
+
+// WithNFTTransfers makes the parser fetch the ERC-721 Transfer and ERC-1155
+// TransferSingle and TransferBatch event logs of every block and store the NFT
+// transfers of subscribed addresses. This costs one extra request per block,
+// shared with WithTokenTransfers.
+func WithNFTTransfers() Option {
+	return func(p *Parser) {
+		p.nftTransfers = true
+	}
+}
+
+// WithInternalTransfers makes the parser trace every block through api and
+// store the ETH that contracts send to or receive from subscribed addresses.
+// The node must serve the chosen trace API. This costs one extra, expensive
+// request per block.
+func WithInternalTransfers(api model.TraceAPI) Option {
+	return func(p *Parser) {
+		p.traceAPI = api
+	}
+}
+
+// WithBloomFilter makes the parser test the subscribed addresses against a
+// block's logs bloom before fetching its logs, and skip the fetch when none of
+// them can be in the block. BloomStats reports how many blocks were skipped.
+// Receipts are unaffected: they are only fetched for blocks holding a
+// subscribed transaction, which may emit no logs at all.
+func WithBloomFilter() Option {
+	return func(p *Parser) {
+		p.bloomFilter = true
+	}
+}
+
+// WithPollInterval sets how often Run checks the chain for new blocks.
+func WithPollInterval(interval time.Duration) Option {
+	return func(p *Parser) {
+		p.pollInterval = interval
+	}
+}
+
+// WithErrorBackoff sets how long Run waits before retrying after a failed
+// parse. The wait doubles on every consecutive failure, up to max.
+func WithErrorBackoff(initial, max time.Duration) Option {
+	return func(p *Parser) {
+		p.errorBackoff = initial
+		p.maxErrorBackoff = max
+	}
+}
+
+// WithHeads makes Run parse as soon as a new head arrives on heads, e.g. from
+// a newHeads subscription, instead of waiting for the next poll.
+func WithHeads(heads <-chan uint64) Option {
+	return func(p *Parser) {
+		p.heads = heads
+	}
+}
+
+// WithLagThreshold sets how many blocks the parser may trail the chain before
+// Status reports it as lagging.
+func WithLagThreshold(blocks uint64) Option {
+	return func(p *Parser) {
+		p.lagThreshold = blocks
+	}
+}
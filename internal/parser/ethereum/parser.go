@@ -1,32 +1,111 @@
 package ethereum
 
 import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
 	"log"
+	"slices"
 	"sync"
+	"sync/atomic"
+	"time"
 	"trustwallet/internal/model"
 	"trustwallet/internal/storage"
 )
 
 //go:generate mockery --name=EthereumClient --case=underscore --output=./mocks
 type EthereumClient interface {
-	GetLatestBlockNumber() (int64, error)
-	GetTransactionsByBlockNumber(blockNumber int64) ([]model.Transaction, error)
+	GetLatestBlockNumber(ctx context.Context) (uint64, error)
+	GetBlockNumberByTag(ctx context.Context, tag model.BlockTag) (uint64, error)
+	GetBlockByNumber(ctx context.Context, blockNumber uint64) (model.Block, error)
+	GetBlocksByNumberRange(ctx context.Context, fromBlock, toBlock uint64) ([]model.Block, error)
+	GetTransactionsByBlockNumber(ctx context.Context, blockNumber uint64) ([]model.Transaction, error)
+	GetBlockReceipts(ctx context.Context, blockNumber uint64) ([]model.Receipt, error)
+	GetLogs(ctx context.Context, blockNumber uint64, topics []string) ([]model.Log, error)
+	GetInternalTransfers(ctx context.Context, blockNumber uint64, api model.TraceAPI) ([]model.InternalTransfer, error)
 }
 
+// DefaultReorgDepth is how many recent blocks the parser remembers to detect
+// and roll back chain reorganizations.
+const DefaultReorgDepth = 64
+
+// DefaultConfirmationDepth is how many blocks must be built on top of a block
+// before its transactions are reported as confirmed.
+const DefaultConfirmationDepth = 12
+
 type Parser struct {
-	mu           *sync.RWMutex
-	currentBlock int64
-	client       EthereumClient
-	storage      storage.Storage
+	mu                *sync.RWMutex
+	currentBlock      uint64
+	client            EthereumClient
+	storage           storage.Storage
+	history           *blockHistory
+	confirmationDepth uint64
+	followTag         model.BlockTag
+	checkpoints       storage.CheckpointStore
+	resumed           bool
+	lastFinalized     uint64
+	windowStart       uint64
+	fetchConcurrency  int
+	fetchWindow       int
+	batchSize         int
+	receipts          bool
+	tokenTransfers    bool
+	nftTransfers      bool
+	traceAPI          model.TraceAPI
+	bloomFilter       bool
+	bloomChecked      *atomic.Int64
+	bloomSkipped      *atomic.Int64
+
+	backfillMu          *sync.Mutex
+	backfills           map[model.Address]BackfillProgress
+	backfillConcurrency int
+
+	runMu           *sync.Mutex
+	running         bool
+	stop            context.CancelCauseFunc
+	stopped         chan struct{}
+	heads           <-chan uint64
+	pollInterval    time.Duration
+	errorBackoff    time.Duration
+	maxErrorBackoff time.Duration
+	lagThreshold    uint64
+	headBlock       uint64
+	lastErr         error
+	lastSuccess     time.Time
 }
 
-func New(currentBlock int64, client EthereumClient, storage storage.Storage) *Parser {
-	return &Parser{
-		mu:           &sync.RWMutex{},
-		currentBlock: currentBlock,
-		client:       client,
-		storage:      storage,
+func New(currentBlock uint64, client EthereumClient, storage storage.Storage, opts ...Option) *Parser {
+	p := &Parser{
+		mu:                &sync.RWMutex{},
+		currentBlock:      currentBlock,
+		client:            client,
+		storage:           storage,
+		history:           newBlockHistory(DefaultReorgDepth),
+		confirmationDepth: DefaultConfirmationDepth,
+		followTag:         model.BlockTagLatest,
+		fetchConcurrency:  DefaultFetchConcurrency,
+		fetchWindow:       DefaultFetchWindow,
+		batchSize:         1,
+		bloomChecked:      &atomic.Int64{},
+		bloomSkipped:      &atomic.Int64{},
+
+		backfillMu:          &sync.Mutex{},
+		backfills:           make(map[model.Address]BackfillProgress),
+		backfillConcurrency: DefaultBackfillConcurrency,
+
+		runMu:           &sync.Mutex{},
+		pollInterval:    DefaultPollInterval,
+		errorBackoff:    DefaultErrorBackoff,
+		maxErrorBackoff: DefaultMaxErrorBackoff,
+		lagThreshold:    DefaultLagThreshold,
+	}
+
+	for _, opt := range opts {
+		opt(p)
 	}
+
+	return p
 }
 
 func (p *Parser) GetCurrentBlock() int {
@@ -36,17 +115,43 @@ func (p *Parser) GetCurrentBlock() int {
 	return int(p.currentBlock)
 }
 
-func (p *Parser) Subscribe(address model.Address) bool {
-	if err := p.storage.AddAddress(address); err != nil {
-		log.Println("Failed to subscribe to address", address, err)
-		return false
+// Subscribe starts recording the transactions of address. It returns an error
+// if address is not a valid address or cannot be stored. Subscribing an
+// address again updates its label and expiry.
+func (p *Parser) Subscribe(address string, opts ...SubscriptionOption) error {
+	return p.SubscribeContext(context.Background(), address, opts...)
+}
+
+func (p *Parser) SubscribeContext(ctx context.Context, address string, opts ...SubscriptionOption) error {
+	parsed, err := model.ParseAddress(address)
+	if err != nil {
+		return err
+	}
+
+	subscription := model.Subscription{Address: parsed, CreatedAt: time.Now().UTC()}
+	for _, opt := range opts {
+		opt(&subscription)
 	}
 
-	return true
+	if err := p.storage.AddSubscription(ctx, subscription); err != nil {
+		return fmt.Errorf("subscribe to %s: %w", parsed, err)
+	}
+
+	return nil
+}
+
+func (p *Parser) GetTransactions(address string) []model.Transaction {
+	return p.GetTransactionsContext(context.Background(), address)
 }
 
-func (p *Parser) GetTransactions(address model.Address) []model.Transaction {
-	transactions, err := p.storage.GetTransactions(address)
+func (p *Parser) GetTransactionsContext(ctx context.Context, address string) []model.Transaction {
+	parsed, err := model.ParseAddress(address)
+	if err != nil {
+		log.Println("Invalid address", err)
+		return nil
+	}
+
+	transactions, err := p.storage.GetTransactions(ctx, parsed)
 	if err != nil {
 		log.Println("Error getting transactions from database", err)
 		return nil
@@ -55,53 +160,527 @@ func (p *Parser) GetTransactions(address model.Address) []model.Transaction {
 	return transactions
 }
 
-func (p *Parser) StartParsing() error {
-	latestBlock, err := p.client.GetLatestBlockNumber()
+// GetTransactionByHash returns a stored transaction of any subscribed address.
+// It returns storage.ErrNotFound if no subscribed address was party to it.
+func (p *Parser) GetTransactionByHash(hash string) (model.Transaction, error) {
+	return p.GetTransactionByHashContext(context.Background(), hash)
+}
+
+func (p *Parser) GetTransactionByHashContext(ctx context.Context, hash string) (model.Transaction, error) {
+	tx, err := p.storage.GetTransactionByHash(ctx, hash)
 	if err != nil {
+		return model.Transaction{}, fmt.Errorf("transaction %s: %w", hash, err)
+	}
+
+	return tx, nil
+}
+
+// QueryTransactions returns a page of the transactions of address selected by
+// query. Pass the page's NextCursor in the next query to continue.
+func (p *Parser) QueryTransactions(address string, query model.TransactionQuery) (model.TransactionPage, error) {
+	return p.QueryTransactionsContext(context.Background(), address, query)
+}
+
+func (p *Parser) QueryTransactionsContext(ctx context.Context, address string, query model.TransactionQuery) (model.TransactionPage, error) {
+	parsed, err := model.ParseAddress(address)
+	if err != nil {
+		return model.TransactionPage{}, err
+	}
+
+	page, err := p.storage.QueryTransactions(ctx, parsed, query)
+	if err != nil {
+		return model.TransactionPage{}, fmt.Errorf("query transactions of %s: %w", parsed, err)
+	}
+
+	return page, nil
+}
+
+// GetTokenTransfers returns the fungible token transfers sent or received by address.
+func (p *Parser) GetTokenTransfers(address string) []model.TokenTransfer {
+	return p.GetTokenTransfersContext(context.Background(), address)
+}
+
+func (p *Parser) GetTokenTransfersContext(ctx context.Context, address string) []model.TokenTransfer {
+	return p.getTokenTransfers(ctx, address, false)
+}
+
+// GetNFTTransfers returns the ERC-721 and ERC-1155 transfers sent or received by address.
+func (p *Parser) GetNFTTransfers(address string) []model.TokenTransfer {
+	return p.GetNFTTransfersContext(context.Background(), address)
+}
+
+func (p *Parser) GetNFTTransfersContext(ctx context.Context, address string) []model.TokenTransfer {
+	return p.getTokenTransfers(ctx, address, true)
+}
+
+func (p *Parser) getTokenTransfers(ctx context.Context, address string, nft bool) []model.TokenTransfer {
+	parsed, err := model.ParseAddress(address)
+	if err != nil {
+		log.Println("Invalid address", err)
+		return nil
+	}
+
+	transfers, err := p.storage.GetTokenTransfers(ctx, parsed)
+	if err != nil {
+		log.Println("Error getting token transfers from database", err)
+		return nil
+	}
+
+	filtered := make([]model.TokenTransfer, 0, len(transfers))
+	for _, transfer := range transfers {
+		if transfer.Standard.IsNFT() == nft {
+			filtered = append(filtered, transfer)
+		}
+	}
+
+	return filtered
+}
+
+// GetInternalTransfers returns the ETH sent or received by address through
+// contract calls.
+func (p *Parser) GetInternalTransfers(address string) []model.InternalTransfer {
+	return p.GetInternalTransfersContext(context.Background(), address)
+}
+
+func (p *Parser) GetInternalTransfersContext(ctx context.Context, address string) []model.InternalTransfer {
+	parsed, err := model.ParseAddress(address)
+	if err != nil {
+		log.Println("Invalid address", err)
+		return nil
+	}
+
+	transfers, err := p.storage.GetInternalTransfers(ctx, parsed)
+	if err != nil {
+		log.Println("Error getting internal transfers from database", err)
+		return nil
+	}
+
+	return transfers
+}
+
+// StartParsing catches up with the chain. Cancelling ctx aborts in-flight
+// requests and stops the catch-up between blocks.
+func (p *Parser) StartParsing(ctx context.Context) error {
+	if err := p.resume(ctx); err != nil {
 		return err
 	}
 
+	latestBlock, err := p.client.GetLatestBlockNumber(ctx)
+	if err != nil {
+		return err
+	}
+
+	targetBlock := latestBlock
+	if p.followTag != model.BlockTagLatest {
+		if targetBlock, err = p.client.GetBlockNumberByTag(ctx, p.followTag); err != nil {
+			return err
+		}
+	}
+
+	p.observeHead(targetBlock)
+
 	if p.currentBlock == 0 {
 		p.mu.Lock()
-		p.currentBlock = latestBlock
+		p.currentBlock = targetBlock
 		p.mu.Unlock()
 	}
 
-	for blockNum := p.currentBlock + 1; blockNum <= latestBlock; blockNum++ {
-		if err := p.parseBlock(blockNum); err != nil {
+	if p.windowStart == 0 {
+		p.mu.Lock()
+		p.windowStart = p.currentBlock + 1
+		p.mu.Unlock()
+	}
+
+	// Statuses are still updated while caught up, unless nothing awaits one.
+	if p.currentBlock >= targetBlock && len(p.history.unfinalized()) == 0 {
+		return nil
+	}
+
+	finalizedBlock, err := p.finalizedBlock(ctx, latestBlock)
+	if err != nil {
+		return err
+	}
+
+	for p.currentBlock < targetBlock {
+		if err := p.parseRange(ctx, p.currentBlock+1, targetBlock, latestBlock, finalizedBlock); err != nil {
 			return err
 		}
+	}
 
-		p.mu.Lock()
-		p.currentBlock = blockNum
-		p.mu.Unlock()
+	return p.updateStatuses(ctx, latestBlock, finalizedBlock)
+}
+
+// finalizedBlock returns the node's finalized block. A node that cannot report
+// it does not stop parsing: the last finalized block it reported is used, or
+// without one the block the confirmation depth below latestBlock.
+func (p *Parser) finalizedBlock(ctx context.Context, latestBlock uint64) (uint64, error) {
+	finalizedBlock, err := p.client.GetBlockNumberByTag(ctx, model.BlockTagFinalized)
+	if err == nil {
+		p.lastFinalized = finalizedBlock
+		return finalizedBlock, nil
+	}
+
+	if ctx.Err() != nil {
+		return 0, ctx.Err()
+	}
+
+	if p.lastFinalized != 0 {
+		log.Println("Finalized block unavailable, using the last known one:", err)
+		return p.lastFinalized, nil
+	}
+
+	log.Println("Finalized block unavailable, using the confirmation depth:", err)
+	return latestBlock - min(latestBlock, p.confirmationDepth), nil
+}
+
+// parseRange fetches the blocks in [fromBlock, toBlock] concurrently and
+// commits them strictly in order. It stops early, with currentBlock rewound,
+// when a reorg is detected, and never advances currentBlock past a block that
+// failed. Once a block starts committing it is finished even if ctx is
+// cancelled, so storage and the checkpoint never disagree.
+func (p *Parser) parseRange(ctx context.Context, fromBlock, toBlock, latestBlock, finalizedBlock uint64) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	commitCtx := context.WithoutCancel(ctx)
+
+	for result := range p.fetchBlocks(ctx, fromBlock, toBlock) {
+		if result.err != nil {
+			return result.err
+		}
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		blockNum, block := result.number, result.value
+
+		if parent, ok := p.history.get(blockNum - 1); ok && parent.hash != block.ParentHash {
+			ancestor, err := p.findCommonAncestor(ctx, blockNum-1)
+			if err != nil {
+				return err
+			}
+
+			log.Println("Chain reorganization detected, rolling back to block", ancestor)
+
+			return p.rollback(ctx, ancestor)
+		}
+
+		if err := p.commitBlock(commitCtx, block, p.statusOf(blockNum, latestBlock, finalizedBlock)); err != nil {
+			return err
+		}
+	}
+
+	// The fetches stop without an error when ctx is cancelled.
+	return ctx.Err()
+}
+
+// resume restores currentBlock from the checkpoint store the first time the
+// parser runs. The checkpointed block and the blocks before it that were not
+// final yet are remembered, so their statuses are still updated and a reorg
+// that happened while the parser was down is still detected and rolled back.
+func (p *Parser) resume(ctx context.Context) error {
+	if p.checkpoints == nil || p.resumed {
+		return nil
+	}
+
+	checkpoint, err := p.checkpoints.LoadCheckpoint(ctx)
+	if errors.Is(err, storage.ErrNoCheckpoint) {
+		p.resumed = true
+		return nil
+	}
+	if err != nil {
+		return err
 	}
 
+	p.history.add(blockRecord{
+		number: checkpoint.BlockNumber,
+		hash:   checkpoint.BlockHash,
+		status: model.TransactionStatusPending,
+	})
+
+	for _, block := range checkpoint.Pending {
+		p.history.add(recordOf(block))
+	}
+
+	p.mu.Lock()
+	p.currentBlock = checkpoint.BlockNumber
+	p.mu.Unlock()
+
+	p.resumed = true
+	log.Println("Resuming from checkpoint at block", checkpoint.BlockNumber)
+
 	return nil
 }
 
-func (p *Parser) parseBlock(blockNumber int64) error {
-	transactionsData, err := p.client.GetTransactionsByBlockNumber(blockNumber)
+func (p *Parser) saveCheckpoint(ctx context.Context, checkpoint model.Checkpoint) error {
+	if p.checkpoints == nil {
+		return nil
+	}
+
+	return p.checkpoints.SaveCheckpoint(ctx, checkpoint)
+}
+
+// checkpointAt returns the checkpoint for the block of record, carrying the
+// remembered blocks up to it that are not final yet.
+func (p *Parser) checkpointAt(record blockRecord) model.Checkpoint {
+	checkpoint := model.Checkpoint{BlockNumber: record.number, BlockHash: record.hash}
+
+	for _, older := range p.history.unfinalized() {
+		if older.number < record.number {
+			checkpoint.Pending = append(checkpoint.Pending, older.pendingBlock())
+		}
+	}
+
+	if record.status != model.TransactionStatusFinalized {
+		checkpoint.Pending = append(checkpoint.Pending, record.pendingBlock())
+	}
+
+	slices.SortFunc(checkpoint.Pending, func(a, b model.PendingBlock) int {
+		return cmp.Compare(a.Number, b.Number)
+	})
+
+	return checkpoint
+}
+
+// commitBlock stores everything block holds for subscribed addresses in a
+// single batch, then advances the parser past it. When the checkpoint store is
+// the storage itself, the checkpoint joins the batch, so a crash can neither
+// leave a block half-written nor move the checkpoint past unwritten data.
+func (p *Parser) commitBlock(ctx context.Context, block model.Block, status model.TransactionStatus) error {
+	record, batch, err := p.parseBlock(ctx, block, status)
 	if err != nil {
 		return err
 	}
 
-	for _, tx := range transactionsData {
-		isFromSubscribed, _ := p.storage.IsSubscribed(tx.From)
-		isToSubscribed, _ := p.storage.IsSubscribed(tx.To)
+	checkpoint := p.checkpointAt(record)
 
-		if isFromSubscribed {
-			if err := p.storage.AddTransaction(tx.From, tx); err != nil {
-				log.Println("Failed to add transaction", tx.From, tx.To, err)
+	checkpointInBatch := p.checkpointsInStorage()
+	if checkpointInBatch {
+		batch.SaveCheckpoint(checkpoint)
+	}
+
+	if !batch.Empty() {
+		if err := p.storage.CommitBatch(ctx, batch); err != nil {
+			return fmt.Errorf("commit block %d: %w", block.Number, err)
+		}
+	}
+
+	p.history.add(record)
+
+	p.mu.Lock()
+	p.currentBlock = block.Number
+	p.mu.Unlock()
+
+	if checkpointInBatch {
+		return nil
+	}
+
+	return p.saveCheckpoint(ctx, checkpoint)
+}
+
+// checkpointsInStorage reports whether checkpoints are kept by the storage
+// itself and can be committed in the same batch as a block's writes.
+func (p *Parser) checkpointsInStorage() bool {
+	store, ok := p.storage.(storage.CheckpointStore)
+	return ok && p.checkpoints == store
+}
+
+// parseBlock collects the writes block needs for subscribed addresses into a
+// batch, along with the record to remember once the batch is committed.
+func (p *Parser) parseBlock(ctx context.Context, block model.Block, status model.TransactionStatus) (blockRecord, storage.Batch, error) {
+	record := blockRecord{
+		number:     block.Number,
+		hash:       block.Hash,
+		parentHash: block.ParentHash,
+		status:     status,
+	}
+
+	var batch storage.Batch
+
+	for _, tx := range block.Transactions {
+		tx.Status = status
+
+		for _, party := range tx.Parties() {
+			subscribed, err := p.storage.IsSubscribed(ctx, party)
+			if err != nil {
+				return blockRecord{}, storage.Batch{}, fmt.Errorf("block %d: %w", block.Number, err)
 			}
+			if !subscribed {
+				continue
+			}
+
+			batch.AddTransaction(party, tx)
+			record.stored = append(record.stored, storedTransaction{address: party, hash: tx.Hash})
 		}
+	}
+
+	var err error
+	if record.transfers, err = p.parseTokenTransfers(ctx, block, status, &batch); err != nil {
+		return blockRecord{}, storage.Batch{}, fmt.Errorf("block %d: %w", block.Number, err)
+	}
+
+	if record.internalTransfers, err = p.parseInternalTransfers(ctx, block, status, &batch); err != nil {
+		return blockRecord{}, storage.Batch{}, fmt.Errorf("block %d: %w", block.Number, err)
+	}
 
-		if isToSubscribed {
-			if err := p.storage.AddTransaction(tx.To, tx); err != nil {
-				log.Println("Failed to add transaction", tx.From, tx.To, err)
+	return record, batch, nil
+}
+
+func (p *Parser) statusOf(blockNumber, latestBlock, finalizedBlock uint64) model.TransactionStatus {
+	switch {
+	case blockNumber <= finalizedBlock:
+		return model.TransactionStatusFinalized
+	case blockNumber+p.confirmationDepth <= latestBlock:
+		return model.TransactionStatusConfirmed
+	default:
+		return model.TransactionStatusPending
+	}
+}
+
+// updateStatuses upgrades the status of stored transactions whose blocks have
+// gained confirmations or became finalized, and forgets finalized blocks that
+// are too old to take part in a reorg. A block's remembered status changes
+// only once its stored activity is updated, so failed updates are retried.
+func (p *Parser) updateStatuses(ctx context.Context, latestBlock, finalizedBlock uint64) error {
+	for _, record := range p.history.unfinalized() {
+		status := p.statusOf(record.number, latestBlock, finalizedBlock)
+		if status == record.status {
+			continue
+		}
+
+		if err := p.storeStatus(ctx, record, status); err != nil {
+			return err
+		}
+
+		// Transactions a backfill attached in the meantime were stored with
+		// the old status.
+		updated := p.history.setStatus(record.number, status)
+		if len(updated.stored) > len(record.stored) {
+			if err := p.storeStatus(ctx, blockRecord{stored: updated.stored[len(record.stored):]}, status); err != nil {
+				return err
 			}
 		}
 	}
 
+	p.history.prune(p.currentBlock)
+
 	return nil
 }
+
+// storeStatus sets the status of everything record stored in a single batch.
+func (p *Parser) storeStatus(ctx context.Context, record blockRecord, status model.TransactionStatus) error {
+	var batch storage.Batch
+	for _, tx := range record.stored {
+		batch.UpdateTransactionStatus(tx.address, tx.hash, status)
+	}
+
+	for _, transfer := range record.transfers {
+		batch.UpdateTokenTransferStatus(transfer.address, transfer.hash, transfer.logIndex, status)
+	}
+
+	for _, transfer := range record.internalTransfers {
+		batch.UpdateInternalTransferStatus(transfer.address, transfer.hash, transfer.index, status)
+	}
+
+	if batch.Empty() {
+		return nil
+	}
+
+	return p.storage.CommitBatch(ctx, batch)
+}
+
+// findCommonAncestor walks back from blockNumber until the canonical chain
+// agrees with the block hash the parser ingested. If the walk runs past the
+// blocks the parser remembers, as after a restart that only remembers the
+// checkpoint, it falls back to the block the reorg depth below blockNumber.
+func (p *Parser) findCommonAncestor(ctx context.Context, blockNumber uint64) (uint64, error) {
+	for number := blockNumber; ; number-- {
+		record, ok := p.history.get(number)
+		if !ok {
+			return p.rewindAncestor(ctx, blockNumber)
+		}
+
+		block, err := p.client.GetBlockByNumber(ctx, number)
+		if err != nil {
+			return 0, err
+		}
+
+		// The genesis block has no parent to walk back to.
+		if block.Hash == record.hash || number == 0 {
+			return number, nil
+		}
+	}
+}
+
+// rewindAncestor takes the canonical block the reorg depth below blockNumber
+// as the common ancestor and remembers it, so the blocks above it are
+// re-ingested, which storing idempotently makes safe. Activity stored for
+// orphaned blocks the parser no longer remembers is left in storage.
+func (p *Parser) rewindAncestor(ctx context.Context, blockNumber uint64) (uint64, error) {
+	ancestor := blockNumber - min(blockNumber, p.history.depth)
+
+	block, err := p.client.GetBlockByNumber(ctx, ancestor)
+	if err != nil {
+		return 0, err
+	}
+
+	log.Println("Reorg reaches below the remembered blocks, re-ingesting from block", ancestor)
+
+	p.history.add(blockRecord{
+		number:     block.Number,
+		hash:       block.Hash,
+		parentHash: block.ParentHash,
+		status:     model.TransactionStatusPending,
+	})
+
+	return ancestor, nil
+}
+
+// rollback removes the activity of every block above ancestor from storage
+// and rewinds currentBlock so it is re-ingested from the canonical chain. The
+// removals and, when the storage keeps it, the checkpoint commit in a single
+// batch. The orphaned blocks are forgotten only once the batch is committed,
+// so a failed rollback is detected and retried on the next run.
+func (p *Parser) rollback(ctx context.Context, ancestor uint64) error {
+	var batch storage.Batch
+	for _, record := range p.history.above(ancestor) {
+		for _, tx := range record.stored {
+			batch.RemoveTransaction(tx.address, tx.hash)
+		}
+
+		for _, transfer := range record.transfers {
+			batch.RemoveTokenTransfer(transfer.address, transfer.hash, transfer.logIndex)
+		}
+
+		for _, transfer := range record.internalTransfers {
+			batch.RemoveInternalTransfer(transfer.address, transfer.hash, transfer.index)
+		}
+	}
+
+	record, _ := p.history.get(ancestor)
+	checkpoint := p.checkpointAt(record)
+
+	checkpointInBatch := p.checkpointsInStorage()
+	if checkpointInBatch {
+		batch.SaveCheckpoint(checkpoint)
+	}
+
+	if !batch.Empty() {
+		if err := p.storage.CommitBatch(ctx, batch); err != nil {
+			return fmt.Errorf("roll back to block %d: %w", ancestor, err)
+		}
+	}
+
+	p.history.truncate(ancestor)
+
+	p.mu.Lock()
+	p.currentBlock = ancestor
+	p.mu.Unlock()
+
+	if checkpointInBatch {
+		return nil
+	}
+
+	return p.saveCheckpoint(ctx, checkpoint)
+}
@@ -1,13 +1,22 @@
 package ethereum_test
 
 import (
+	"context"
+	"encoding/hex"
 	"errors"
+	"fmt"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"math/big"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 	"trustwallet/internal/model"
 	"trustwallet/internal/parser/ethereum"
 	"trustwallet/internal/parser/ethereum/mocks"
+	"trustwallet/internal/storage"
+	"trustwallet/internal/storage/inmem"
 	storagemocks "trustwallet/internal/storage/mocks"
 )
 
@@ -24,13 +33,13 @@ func TestParser_Subscribe(t *testing.T) {
 	mockStorage := storagemocks.NewStorage(t)
 	parser := ethereum.New(0, nil, mockStorage)
 
-	testAddress := model.Address("0xTestAddress")
+	mockStorage.On("AddSubscription", mock.Anything, mock.MatchedBy(func(s model.Subscription) bool {
+		return s.Address == "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed" && !s.CreatedAt.IsZero() && s.Label == "" && s.ExpiresAt == nil
+	})).Return(nil)
 
-	mockStorage.On("AddAddress", testAddress).Return(nil)
+	err := parser.Subscribe("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed")
 
-	success := parser.Subscribe(testAddress)
-
-	assert.True(t, success, "Subscribe() should return true on success")
+	assert.NoError(t, err, "Subscribe() should store the address in canonical form")
 	mockStorage.AssertExpectations(t)
 }
 
@@ -38,42 +47,134 @@ func TestParser_Subscribe_Failure(t *testing.T) {
 	mockStorage := storagemocks.NewStorage(t)
 	parser := ethereum.New(0, nil, mockStorage)
 
-	testAddress := model.Address("0xTestAddress")
+	testAddress := model.Address("0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed")
 	mockError := errors.New("storage error")
 
-	mockStorage.On("AddAddress", testAddress).Return(mockError)
+	mockStorage.On("AddSubscription", mock.Anything, mock.MatchedBy(func(s model.Subscription) bool { return s.Address == testAddress })).Return(mockError)
 
-	success := parser.Subscribe(testAddress)
+	err := parser.Subscribe(string(testAddress))
 
-	assert.False(t, success, "Subscribe() should return false on failure")
+	assert.ErrorIs(t, err, mockError, "Subscribe() should fail when storage fails")
 	mockStorage.AssertExpectations(t)
 }
 
+func TestParser_Subscribe_InvalidAddress(t *testing.T) {
+	parser := ethereum.New(0, nil, storagemocks.NewStorage(t))
+
+	assert.ErrorIs(t, parser.Subscribe("0xYourEthereumAddress"), model.ErrInvalidAddress)
+	assert.ErrorIs(t, parser.Subscribe("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD"), model.ErrInvalidChecksum)
+}
+
+func TestParser_GetTransactionByHash(t *testing.T) {
+	mockStorage := storagemocks.NewStorage(t)
+	parser := ethereum.New(0, nil, mockStorage)
+
+	tx := model.Transaction{Hash: "0xTxHash1", From: "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed"}
+	mockStorage.On("GetTransactionByHash", mock.Anything, "0xTxHash1").Return(tx, nil)
+	mockStorage.On("GetTransactionByHash", mock.Anything, "0xTxHash2").Return(model.Transaction{}, storage.ErrNotFound)
+
+	got, err := parser.GetTransactionByHash("0xTxHash1")
+	assert.NoError(t, err)
+	assert.Equal(t, tx, got)
+
+	_, err = parser.GetTransactionByHash("0xTxHash2")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+func TestParser_QueryTransactions(t *testing.T) {
+	mockStorage := storagemocks.NewStorage(t)
+	parser := ethereum.New(0, nil, mockStorage)
+
+	address := model.Address("0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed")
+	query := model.TransactionQuery{Direction: model.DirectionIncoming, Limit: 10}
+	page := model.TransactionPage{Transactions: []model.Transaction{{Hash: "0xTxHash1", To: address}}, NextCursor: "100:0:0xTxHash1"}
+	mockStorage.On("QueryTransactions", mock.Anything, address, query).Return(page, nil).Once()
+	mockStorage.On("QueryTransactions", mock.Anything, address, model.TransactionQuery{Cursor: "bogus"}).Return(model.TransactionPage{}, model.ErrInvalidCursor).Once()
+
+	got, err := parser.QueryTransactions("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", query)
+	assert.NoError(t, err)
+	assert.Equal(t, page, got)
+
+	_, err = parser.QueryTransactions(string(address), model.TransactionQuery{Cursor: "bogus"})
+	assert.ErrorIs(t, err, model.ErrInvalidCursor)
+
+	_, err = parser.QueryTransactions("0xYourEthereumAddress", query)
+	assert.ErrorIs(t, err, model.ErrInvalidAddress)
+}
+
+func TestParser_Subscribe_LabelAndExpiry(t *testing.T) {
+	mockStorage := storagemocks.NewStorage(t)
+	parser := ethereum.New(0, nil, mockStorage)
+
+	expiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	mockStorage.On("AddSubscription", mock.Anything, mock.MatchedBy(func(s model.Subscription) bool {
+		return s.Address == "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed" && s.Label == "treasury" && s.ExpiresAt != nil && s.ExpiresAt.Equal(expiresAt)
+	})).Return(nil)
+
+	err := parser.Subscribe("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", ethereum.WithLabel("treasury"), ethereum.WithExpiry(expiresAt))
+
+	assert.NoError(t, err)
+}
+
+func TestParser_Unsubscribe(t *testing.T) {
+	mockStorage := storagemocks.NewStorage(t)
+	parser := ethereum.New(0, nil, mockStorage)
+
+	address := model.Address("0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed")
+	mockStorage.On("RemoveSubscription", mock.Anything, address, true).Return(nil).Once()
+	mockStorage.On("RemoveSubscription", mock.Anything, address, false).Return(storage.ErrNotSubscribed).Once()
+
+	assert.NoError(t, parser.Unsubscribe("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", true))
+	assert.ErrorIs(t, parser.Unsubscribe(string(address), false), storage.ErrNotSubscribed)
+	assert.ErrorIs(t, parser.Unsubscribe("0xYourEthereumAddress", false), model.ErrInvalidAddress)
+}
+
+func TestParser_ListSubscriptions(t *testing.T) {
+	mockStorage := storagemocks.NewStorage(t)
+	parser := ethereum.New(0, nil, mockStorage)
+
+	first := model.Subscription{Address: "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", Label: "treasury"}
+	second := model.Subscription{Address: "0xfb6916095ca1df60bb79ce92ce3ea74c37c5d359"}
+	mockStorage.On("ListSubscriptions", mock.Anything, model.Address(""), 1).Return([]model.Subscription{first}, nil)
+	mockStorage.On("ListSubscriptions", mock.Anything, first.Address, 1).Return([]model.Subscription{second}, nil)
+
+	page, err := parser.ListSubscriptions("", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.Subscription{first}, page)
+
+	page, err = parser.ListSubscriptions(string(page[0].Address), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []model.Subscription{second}, page)
+
+	_, err = parser.ListSubscriptions("0xYourEthereumAddress", 1)
+	assert.ErrorIs(t, err, model.ErrInvalidAddress)
+}
+
 func TestParser_GetTransactions(t *testing.T) {
 	mockStorage := storagemocks.NewStorage(t)
 	parser := ethereum.New(0, nil, mockStorage)
 
-	testAddress := model.Address("0xTestAddress")
+	testAddress := model.Address("0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed")
 	expectedTransactions := []model.Transaction{
 		{
 			Hash:        "0xHash1",
 			From:        testAddress,
 			To:          "0xAddress2",
-			Value:       "100",
-			BlockNumber: "1",
+			Value:       big.NewInt(100),
+			BlockNumber: 1,
 		},
 		{
 			Hash:        "0xHash2",
 			From:        "0xAddress3",
 			To:          testAddress,
-			Value:       "200",
-			BlockNumber: "2",
+			Value:       big.NewInt(200),
+			BlockNumber: 2,
 		},
 	}
 
-	mockStorage.On("GetTransactions", testAddress).Return(expectedTransactions, nil)
+	mockStorage.On("GetTransactions", mock.Anything, testAddress).Return(expectedTransactions, nil)
 
-	actualTransactions := parser.GetTransactions(testAddress)
+	actualTransactions := parser.GetTransactions("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed")
 
 	assert.Equal(t, expectedTransactions, actualTransactions, "GetTransactions() should return the correct transactions")
 	mockStorage.AssertExpectations(t)
@@ -83,12 +184,12 @@ func TestParser_GetTransactions_Error(t *testing.T) {
 	mockStorage := storagemocks.NewStorage(t)
 	parser := ethereum.New(0, nil, mockStorage)
 
-	testAddress := model.Address("0xTestAddress")
+	testAddress := model.Address("0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed")
 	mockError := errors.New("storage error")
 
-	mockStorage.On("GetTransactions", testAddress).Return(nil, mockError)
+	mockStorage.On("GetTransactions", mock.Anything, testAddress).Return(nil, mockError)
 
-	actualTransactions := parser.GetTransactions(testAddress)
+	actualTransactions := parser.GetTransactions(string(testAddress))
 
 	assert.Nil(t, actualTransactions, "GetTransactions() should return nil when storage returns an error")
 	mockStorage.AssertExpectations(t)
@@ -98,9 +199,9 @@ func TestParser_StartParsing_InitialBlock(t *testing.T) {
 	mockClient := mocks.NewEthereumClient(t)
 	parser := ethereum.New(0, mockClient, nil)
 
-	mockClient.On("GetLatestBlockNumber").Return(int64(100), nil)
+	mockClient.On("GetLatestBlockNumber", mock.Anything).Return(uint64(100), nil)
 
-	err := parser.StartParsing()
+	err := parser.StartParsing(context.Background())
 
 	assert.NoError(t, err)
 	assert.Equal(t, 100, parser.GetCurrentBlock(), "currentBlock should be set to latestBlock when it is 0")
@@ -112,15 +213,16 @@ func TestParser_StartParsing_ParseBlocks(t *testing.T) {
 	mockStorage := storagemocks.NewStorage(t)
 	parser := ethereum.New(98, mockClient, mockStorage)
 
-	mockClient.On("GetLatestBlockNumber").Return(int64(100), nil)
+	mockClient.On("GetLatestBlockNumber", mock.Anything).Return(uint64(100), nil)
+	mockClient.On("GetBlockNumberByTag", mock.Anything, model.BlockTagFinalized).Return(uint64(50), nil)
 
 	txsBlock99 := []model.Transaction{
 		{
 			Hash:        "0xHash99",
 			From:        "0xSubscribedAddress",
 			To:          "0xAddress2",
-			Value:       "100",
-			BlockNumber: "99",
+			Value:       big.NewInt(100),
+			BlockNumber: 99,
 		},
 	}
 	txsBlock100 := []model.Transaction{
@@ -128,22 +230,22 @@ func TestParser_StartParsing_ParseBlocks(t *testing.T) {
 			Hash:        "0xHash100",
 			From:        "0xAddress3",
 			To:          "0xSubscribedAddress",
-			Value:       "200",
-			BlockNumber: "100",
+			Value:       big.NewInt(200),
+			BlockNumber: 100,
 		},
 	}
 
-	mockClient.On("GetTransactionsByBlockNumber", int64(99)).Return(txsBlock99, nil)
-	mockClient.On("GetTransactionsByBlockNumber", int64(100)).Return(txsBlock100, nil)
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(99)).Return(model.Block{Number: 99, Hash: "0xBlock99", ParentHash: "0xBlock98", Transactions: txsBlock99}, nil)
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(100)).Return(model.Block{Number: 100, Hash: "0xBlock100", ParentHash: "0xBlock99", Transactions: txsBlock100}, nil)
 
 	// Simulate subscribed address
-	mockStorage.On("IsSubscribed", model.Address("0xSubscribedAddress")).Return(true, nil).Twice()
-	mockStorage.On("IsSubscribed", mock.Anything).Return(false, nil)
+	mockStorage.On("IsSubscribed", mock.Anything, model.Address("0xSubscribedAddress")).Return(true, nil).Twice()
+	mockStorage.On("IsSubscribed", mock.Anything, mock.Anything).Return(false, nil)
 
-	// Expect AddTransaction to be called
-	mockStorage.On("AddTransaction", model.Address("0xSubscribedAddress"), mock.AnythingOfType("model.Transaction")).Return(nil).Twice()
+	// Expect one commit per block holding a subscribed transaction
+	mockStorage.On("CommitBatch", mock.Anything, mock.AnythingOfType("storage.Batch")).Return(nil).Twice()
 
-	err := parser.StartParsing()
+	err := parser.StartParsing(context.Background())
 
 	assert.NoError(t, err)
 	assert.Equal(t, 100, parser.GetCurrentBlock(), "currentBlock should be updated to latestBlock")
@@ -157,10 +259,1345 @@ func TestParser_StartParsing_ClientError(t *testing.T) {
 	parser := ethereum.New(98, mockClient, nil)
 
 	mockError := errors.New("client error")
-	mockClient.On("GetLatestBlockNumber").Return(int64(0), mockError)
+	mockClient.On("GetLatestBlockNumber", mock.Anything).Return(uint64(0), mockError)
 
-	err := parser.StartParsing()
+	err := parser.StartParsing(context.Background())
 
 	assert.EqualError(t, err, "client error")
 	mockClient.AssertExpectations(t)
 }
+
+func TestParser_StartParsing_Reorg(t *testing.T) {
+	mockClient := mocks.NewEthereumClient(t)
+	mockStorage := storagemocks.NewStorage(t)
+	parser := ethereum.New(98, mockClient, mockStorage)
+
+	subscribed := model.Address("0xSubscribedAddress")
+	orphanedTx := model.Transaction{Hash: "0xOrphaned", From: subscribed, To: "0xAddress2", BlockNumber: 100}
+	canonicalTx := model.Transaction{Hash: "0xCanonical", From: subscribed, To: "0xAddress2", BlockNumber: 100}
+
+	mockClient.On("GetBlockNumberByTag", mock.Anything, model.BlockTagFinalized).Return(uint64(50), nil)
+	mockStorage.On("IsSubscribed", mock.Anything, subscribed).Return(true, nil)
+	mockStorage.On("IsSubscribed", mock.Anything, mock.Anything).Return(false, nil)
+
+	// First pass ingests blocks 99 and 100.
+	mockClient.On("GetLatestBlockNumber", mock.Anything).Return(uint64(100), nil).Once()
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(99)).Return(model.Block{Number: 99, Hash: "0xBlock99", ParentHash: "0xBlock98"}, nil).Once()
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(100)).Return(model.Block{Number: 100, Hash: "0xBlock100", ParentHash: "0xBlock99", Transactions: []model.Transaction{orphanedTx}}, nil).Once()
+	mockStorage.On("CommitBatch", mock.Anything, transactionBatch(subscribed, withStatus(orphanedTx, model.TransactionStatusPending))).Return(nil).Once()
+
+	assert.NoError(t, parser.StartParsing(context.Background()))
+	assert.Equal(t, 100, parser.GetCurrentBlock())
+
+	// Block 101 builds on a replacement of block 100; block 99 is still canonical.
+	mockClient.On("GetLatestBlockNumber", mock.Anything).Return(uint64(101), nil).Once()
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(101)).Return(model.Block{Number: 101, Hash: "0xBlock101", ParentHash: "0xBlock100b"}, nil).Once()
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(100)).Return(model.Block{Number: 100, Hash: "0xBlock100b", ParentHash: "0xBlock99", Transactions: []model.Transaction{canonicalTx}}, nil).Twice()
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(99)).Return(model.Block{Number: 99, Hash: "0xBlock99", ParentHash: "0xBlock98"}, nil).Once()
+	mockStorage.On("CommitBatch", mock.Anything, removalBatch(subscribed, "0xOrphaned")).Return(nil).Once()
+	mockStorage.On("CommitBatch", mock.Anything, transactionBatch(subscribed, withStatus(canonicalTx, model.TransactionStatusPending))).Return(nil).Once()
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(101)).Return(model.Block{Number: 101, Hash: "0xBlock101", ParentHash: "0xBlock100b"}, nil).Once()
+
+	assert.NoError(t, parser.StartParsing(context.Background()))
+	assert.Equal(t, 101, parser.GetCurrentBlock(), "currentBlock should reach the new head after the rollback")
+	mockClient.AssertExpectations(t)
+	mockStorage.AssertExpectations(t)
+}
+
+func TestParser_StartParsing_ReorgRemoveError(t *testing.T) {
+	mockClient := mocks.NewEthereumClient(t)
+	mockStorage := storagemocks.NewStorage(t)
+	parser := ethereum.New(98, mockClient, mockStorage)
+
+	subscribed := model.Address("0xSubscribedAddress")
+	orphanedTx := model.Transaction{Hash: "0xOrphaned", From: subscribed, To: "0xAddress2", BlockNumber: 100}
+	canonicalTx := model.Transaction{Hash: "0xCanonical", From: subscribed, To: "0xAddress2", BlockNumber: 100}
+
+	mockClient.On("GetBlockNumberByTag", mock.Anything, model.BlockTagFinalized).Return(uint64(50), nil)
+	mockStorage.On("IsSubscribed", mock.Anything, subscribed).Return(true, nil)
+	mockStorage.On("IsSubscribed", mock.Anything, mock.Anything).Return(false, nil)
+
+	mockClient.On("GetLatestBlockNumber", mock.Anything).Return(uint64(100), nil).Once()
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(99)).Return(model.Block{Number: 99, Hash: "0xBlock99", ParentHash: "0xBlock98"}, nil).Once()
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(100)).Return(model.Block{Number: 100, Hash: "0xBlock100", ParentHash: "0xBlock99", Transactions: []model.Transaction{orphanedTx}}, nil).Once()
+	mockStorage.On("CommitBatch", mock.Anything, transactionBatch(subscribed, withStatus(orphanedTx, model.TransactionStatusPending))).Return(nil).Once()
+
+	assert.NoError(t, parser.StartParsing(context.Background()))
+
+	// Block 100 is replaced, and removing its orphaned transaction fails once.
+	mockClient.On("GetLatestBlockNumber", mock.Anything).Return(uint64(101), nil)
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(101)).Return(model.Block{Number: 101, Hash: "0xBlock101", ParentHash: "0xBlock100b"}, nil)
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(100)).Return(model.Block{Number: 100, Hash: "0xBlock100b", ParentHash: "0xBlock99", Transactions: []model.Transaction{canonicalTx}}, nil)
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(99)).Return(model.Block{Number: 99, Hash: "0xBlock99", ParentHash: "0xBlock98"}, nil)
+
+	mockError := errors.New("disk full")
+	mockStorage.On("CommitBatch", mock.Anything, removalBatch(subscribed, "0xOrphaned")).Return(mockError).Once()
+
+	err := parser.StartParsing(context.Background())
+
+	assert.ErrorIs(t, err, mockError)
+	assert.Equal(t, 100, parser.GetCurrentBlock(), "currentBlock should not move when the rollback fails")
+
+	// The next run detects the reorg again and finishes the rollback.
+	mockStorage.On("CommitBatch", mock.Anything, removalBatch(subscribed, "0xOrphaned")).Return(nil).Once()
+	mockStorage.On("CommitBatch", mock.Anything, transactionBatch(subscribed, withStatus(canonicalTx, model.TransactionStatusPending))).Return(nil).Once()
+
+	assert.NoError(t, parser.StartParsing(context.Background()))
+	assert.Equal(t, 101, parser.GetCurrentBlock())
+	mockStorage.AssertExpectations(t)
+}
+
+func TestParser_StartParsing_ReorgBeyondHistory(t *testing.T) {
+	mockClient := mocks.NewEthereumClient(t)
+	parser := ethereum.New(99, mockClient, nil, ethereum.WithReorgDepth(2))
+
+	mockClient.On("GetBlockNumberByTag", mock.Anything, model.BlockTagFinalized).Return(uint64(50), nil)
+
+	mockClient.On("GetLatestBlockNumber", mock.Anything).Return(uint64(100), nil).Once()
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(100)).Return(model.Block{Number: 100, Hash: "0xBlock100", ParentHash: "0xBlock99"}, nil).Once()
+
+	assert.NoError(t, parser.StartParsing(context.Background()))
+
+	// The replacement chain diverges below block 100, which the parser never
+	// saw, so it rewinds by the reorg depth and re-ingests from there.
+	mockClient.On("GetLatestBlockNumber", mock.Anything).Return(uint64(101), nil).Once()
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(101)).Return(model.Block{Number: 101, Hash: "0xBlock101", ParentHash: "0xBlock100b"}, nil)
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(100)).Return(model.Block{Number: 100, Hash: "0xBlock100b", ParentHash: "0xBlock99b"}, nil)
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(99)).Return(model.Block{Number: 99, Hash: "0xBlock99b", ParentHash: "0xBlock98"}, nil).Once()
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(98)).Return(model.Block{Number: 98, Hash: "0xBlock98", ParentHash: "0xBlock97"}, nil).Once()
+
+	assert.NoError(t, parser.StartParsing(context.Background()))
+	assert.Equal(t, 101, parser.GetCurrentBlock())
+	mockClient.AssertExpectations(t)
+}
+
+func TestParser_StartParsing_ResumeAfterReorg(t *testing.T) {
+	mockClient := mocks.NewEthereumClient(t)
+	store := inmem.New()
+	parser := ethereum.New(0, mockClient, store, ethereum.WithCheckpointStore(store), ethereum.WithReorgDepth(2))
+
+	subscribed := model.Address("0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed")
+	tx := model.Transaction{Hash: "0xHash98b", From: "0xfb6916095ca1df60bb79ce92ce3ea74c37c5d359", To: subscribed, BlockNumber: 98}
+	assert.NoError(t, parser.Subscribe(string(subscribed)))
+
+	// Block 98 was checkpointed, then replaced while the parser was down.
+	assert.NoError(t, store.SaveCheckpoint(context.Background(), model.Checkpoint{BlockNumber: 98, BlockHash: "0xBlock98"}))
+
+	mockClient.On("GetLatestBlockNumber", mock.Anything).Return(uint64(100), nil)
+	mockClient.On("GetBlockNumberByTag", mock.Anything, model.BlockTagFinalized).Return(uint64(50), nil)
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(96)).Return(model.Block{Number: 96, Hash: "0xBlock96", ParentHash: "0xBlock95"}, nil)
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(97)).Return(model.Block{Number: 97, Hash: "0xBlock97", ParentHash: "0xBlock96"}, nil)
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(98)).Return(model.Block{Number: 98, Hash: "0xBlock98b", ParentHash: "0xBlock97", Transactions: []model.Transaction{tx}}, nil)
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(99)).Return(model.Block{Number: 99, Hash: "0xBlock99", ParentHash: "0xBlock98b"}, nil)
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(100)).Return(model.Block{Number: 100, Hash: "0xBlock100", ParentHash: "0xBlock99"}, nil)
+
+	assert.NoError(t, parser.StartParsing(context.Background()))
+	assert.Equal(t, 100, parser.GetCurrentBlock())
+	assert.Equal(t, []model.Transaction{withStatus(tx, model.TransactionStatusPending)}, parser.GetTransactions(string(subscribed)))
+
+	checkpoint, err := store.LoadCheckpoint(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, model.Checkpoint{BlockNumber: 100, BlockHash: "0xBlock100", Pending: []model.PendingBlock{
+		pendingBlock(96, "0xBlock96", "0xBlock95"),
+		pendingBlock(97, "0xBlock97", "0xBlock96"),
+		pendingBlock(98, "0xBlock98b", "0xBlock97", model.ActivityKey{Address: subscribed, Hash: tx.Hash}),
+		pendingBlock(99, "0xBlock99", "0xBlock98b"),
+		pendingBlock(100, "0xBlock100", "0xBlock99"),
+	}}, checkpoint)
+}
+
+func TestParser_StartParsing_RestartUpdatesStatuses(t *testing.T) {
+	store := inmem.New()
+	subscribed := model.Address("0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed")
+	tx := model.Transaction{Hash: "0xHash101", From: "0xfb6916095ca1df60bb79ce92ce3ea74c37c5d359", To: subscribed, BlockNumber: 101}
+	block101 := model.Block{Number: 101, Hash: "0xBlock101", ParentHash: "0xBlock100", Transactions: []model.Transaction{tx}}
+
+	mockClient := mocks.NewEthereumClient(t)
+	parser := ethereum.New(100, mockClient, store, ethereum.WithCheckpointStore(store))
+	assert.NoError(t, parser.Subscribe(string(subscribed)))
+
+	mockClient.On("GetLatestBlockNumber", mock.Anything).Return(uint64(101), nil)
+	mockClient.On("GetBlockNumberByTag", mock.Anything, model.BlockTagFinalized).Return(uint64(50), nil)
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(101)).Return(block101, nil)
+
+	assert.NoError(t, parser.StartParsing(context.Background()))
+	assert.Equal(t, []model.Transaction{withStatus(tx, model.TransactionStatusPending)}, parser.GetTransactions(string(subscribed)))
+
+	// A restarted parser finds block 101 finalized.
+	mockClient = mocks.NewEthereumClient(t)
+	parser = ethereum.New(0, mockClient, store, ethereum.WithCheckpointStore(store))
+
+	mockClient.On("GetLatestBlockNumber", mock.Anything).Return(uint64(103), nil)
+	mockClient.On("GetBlockNumberByTag", mock.Anything, model.BlockTagFinalized).Return(uint64(102), nil)
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(102)).Return(model.Block{Number: 102, Hash: "0xBlock102", ParentHash: "0xBlock101"}, nil)
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(103)).Return(model.Block{Number: 103, Hash: "0xBlock103", ParentHash: "0xBlock102"}, nil)
+
+	assert.NoError(t, parser.StartParsing(context.Background()))
+	assert.Equal(t, []model.Transaction{withStatus(tx, model.TransactionStatusFinalized)}, parser.GetTransactions(string(subscribed)))
+}
+
+func TestParser_StartParsing_RestartRollsBackOrphans(t *testing.T) {
+	store := inmem.New()
+	subscribed := model.Address("0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed")
+	orphanedTx := model.Transaction{Hash: "0xOrphaned", From: "0xfb6916095ca1df60bb79ce92ce3ea74c37c5d359", To: subscribed, BlockNumber: 101}
+
+	mockClient := mocks.NewEthereumClient(t)
+	parser := ethereum.New(99, mockClient, store, ethereum.WithCheckpointStore(store))
+	assert.NoError(t, parser.Subscribe(string(subscribed)))
+
+	mockClient.On("GetLatestBlockNumber", mock.Anything).Return(uint64(102), nil)
+	mockClient.On("GetBlockNumberByTag", mock.Anything, model.BlockTagFinalized).Return(uint64(50), nil)
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(100)).Return(model.Block{Number: 100, Hash: "0xBlock100", ParentHash: "0xBlock99"}, nil)
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(101)).Return(model.Block{Number: 101, Hash: "0xBlock101", ParentHash: "0xBlock100", Transactions: []model.Transaction{orphanedTx}}, nil)
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(102)).Return(model.Block{Number: 102, Hash: "0xBlock102", ParentHash: "0xBlock101"}, nil)
+
+	assert.NoError(t, parser.StartParsing(context.Background()))
+
+	// Blocks 101 and 102 are replaced while the parser is down.
+	mockClient = mocks.NewEthereumClient(t)
+	parser = ethereum.New(0, mockClient, store, ethereum.WithCheckpointStore(store))
+
+	mockClient.On("GetLatestBlockNumber", mock.Anything).Return(uint64(103), nil)
+	mockClient.On("GetBlockNumberByTag", mock.Anything, model.BlockTagFinalized).Return(uint64(50), nil)
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(100)).Return(model.Block{Number: 100, Hash: "0xBlock100", ParentHash: "0xBlock99"}, nil)
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(101)).Return(model.Block{Number: 101, Hash: "0xBlock101b", ParentHash: "0xBlock100"}, nil)
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(102)).Return(model.Block{Number: 102, Hash: "0xBlock102b", ParentHash: "0xBlock101b"}, nil)
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(103)).Return(model.Block{Number: 103, Hash: "0xBlock103", ParentHash: "0xBlock102b"}, nil)
+
+	assert.NoError(t, parser.StartParsing(context.Background()))
+	assert.NoError(t, parser.StartParsing(context.Background()))
+	assert.Equal(t, 103, parser.GetCurrentBlock())
+	assert.Empty(t, parser.GetTransactions(string(subscribed)), "the orphaned transaction should be rolled back after the restart")
+}
+
+func TestParser_StartParsing_ConfirmationStatus(t *testing.T) {
+	mockClient := mocks.NewEthereumClient(t)
+	mockStorage := storagemocks.NewStorage(t)
+	parser := ethereum.New(99, mockClient, mockStorage, ethereum.WithConfirmationDepth(2))
+
+	subscribed := model.Address("0xSubscribedAddress")
+	tx := model.Transaction{Hash: "0xHash100", From: "0xAddress2", To: subscribed, BlockNumber: 100}
+
+	mockStorage.On("IsSubscribed", mock.Anything, subscribed).Return(true, nil)
+	mockStorage.On("IsSubscribed", mock.Anything, mock.Anything).Return(false, nil)
+
+	// Block 100 is ingested at the head, so it is pending.
+	mockClient.On("GetLatestBlockNumber", mock.Anything).Return(uint64(100), nil).Once()
+	mockClient.On("GetBlockNumberByTag", mock.Anything, model.BlockTagFinalized).Return(uint64(90), nil).Once()
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(100)).Return(model.Block{Number: 100, Hash: "0xBlock100", ParentHash: "0xBlock99", Transactions: []model.Transaction{tx}}, nil).Once()
+	mockStorage.On("CommitBatch", mock.Anything, transactionBatch(subscribed, withStatus(tx, model.TransactionStatusPending))).Return(nil).Once()
+
+	assert.NoError(t, parser.StartParsing(context.Background()))
+
+	// Two blocks later it has enough confirmations.
+	mockClient.On("GetLatestBlockNumber", mock.Anything).Return(uint64(102), nil).Once()
+	mockClient.On("GetBlockNumberByTag", mock.Anything, model.BlockTagFinalized).Return(uint64(91), nil).Once()
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(101)).Return(model.Block{Number: 101, Hash: "0xBlock101", ParentHash: "0xBlock100"}, nil).Once()
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(102)).Return(model.Block{Number: 102, Hash: "0xBlock102", ParentHash: "0xBlock101"}, nil).Once()
+	mockStorage.On("CommitBatch", mock.Anything, statusBatch(subscribed, model.TransactionStatusConfirmed, "0xHash100")).Return(nil).Once()
+
+	assert.NoError(t, parser.StartParsing(context.Background()))
+
+	// Once the finalized tag passes block 100 it is finalized.
+	mockClient.On("GetLatestBlockNumber", mock.Anything).Return(uint64(103), nil).Once()
+	mockClient.On("GetBlockNumberByTag", mock.Anything, model.BlockTagFinalized).Return(uint64(100), nil).Once()
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(103)).Return(model.Block{Number: 103, Hash: "0xBlock103", ParentHash: "0xBlock102"}, nil).Once()
+	mockStorage.On("CommitBatch", mock.Anything, statusBatch(subscribed, model.TransactionStatusFinalized, "0xHash100")).Return(nil).Once()
+
+	assert.NoError(t, parser.StartParsing(context.Background()))
+	assert.Equal(t, 103, parser.GetCurrentBlock())
+}
+
+func TestParser_StartParsing_StatusUpdatedWhileCaughtUp(t *testing.T) {
+	mockClient := mocks.NewEthereumClient(t)
+	mockStorage := storagemocks.NewStorage(t)
+	parser := ethereum.New(99, mockClient, mockStorage)
+
+	subscribed := model.Address("0xSubscribedAddress")
+	tx := model.Transaction{Hash: "0xHash100", From: "0xAddress2", To: subscribed, BlockNumber: 100}
+
+	mockStorage.On("IsSubscribed", mock.Anything, subscribed).Return(true, nil)
+	mockStorage.On("IsSubscribed", mock.Anything, mock.Anything).Return(false, nil)
+	mockClient.On("GetLatestBlockNumber", mock.Anything).Return(uint64(100), nil)
+
+	mockClient.On("GetBlockNumberByTag", mock.Anything, model.BlockTagFinalized).Return(uint64(90), nil).Once()
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(100)).Return(model.Block{Number: 100, Hash: "0xBlock100", ParentHash: "0xBlock99", Transactions: []model.Transaction{tx}}, nil).Once()
+	mockStorage.On("CommitBatch", mock.Anything, transactionBatch(subscribed, withStatus(tx, model.TransactionStatusPending))).Return(nil).Once()
+
+	assert.NoError(t, parser.StartParsing(context.Background()))
+
+	// No new block arrives, but block 100 becomes finalized.
+	mockClient.On("GetBlockNumberByTag", mock.Anything, model.BlockTagFinalized).Return(uint64(100), nil).Once()
+	mockStorage.On("CommitBatch", mock.Anything, statusBatch(subscribed, model.TransactionStatusFinalized, "0xHash100")).Return(nil).Once()
+
+	assert.NoError(t, parser.StartParsing(context.Background()))
+	mockStorage.AssertExpectations(t)
+}
+
+func TestParser_StartParsing_FinalizedTagUnavailable(t *testing.T) {
+	mockClient := mocks.NewEthereumClient(t)
+	mockStorage := storagemocks.NewStorage(t)
+	parser := ethereum.New(99, mockClient, mockStorage, ethereum.WithConfirmationDepth(2))
+
+	subscribed := model.Address("0xSubscribedAddress")
+	tx := model.Transaction{Hash: "0xHash100", From: "0xAddress2", To: subscribed, BlockNumber: 100}
+
+	mockStorage.On("IsSubscribed", mock.Anything, subscribed).Return(true, nil)
+	mockStorage.On("IsSubscribed", mock.Anything, mock.Anything).Return(false, nil)
+	mockClient.On("GetBlockNumberByTag", mock.Anything, model.BlockTagFinalized).Return(uint64(0), errors.New("finalized block not found"))
+
+	mockClient.On("GetLatestBlockNumber", mock.Anything).Return(uint64(100), nil).Once()
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(100)).Return(model.Block{Number: 100, Hash: "0xBlock100", ParentHash: "0xBlock99", Transactions: []model.Transaction{tx}}, nil).Once()
+	mockStorage.On("CommitBatch", mock.Anything, transactionBatch(subscribed, withStatus(tx, model.TransactionStatusPending))).Return(nil).Once()
+
+	assert.NoError(t, parser.StartParsing(context.Background()))
+
+	// Without the tag, blocks the confirmation depth deep count as finalized.
+	mockClient.On("GetLatestBlockNumber", mock.Anything).Return(uint64(102), nil).Once()
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(101)).Return(model.Block{Number: 101, Hash: "0xBlock101", ParentHash: "0xBlock100"}, nil).Once()
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(102)).Return(model.Block{Number: 102, Hash: "0xBlock102", ParentHash: "0xBlock101"}, nil).Once()
+	mockStorage.On("CommitBatch", mock.Anything, statusBatch(subscribed, model.TransactionStatusFinalized, "0xHash100")).Return(nil).Once()
+
+	assert.NoError(t, parser.StartParsing(context.Background()))
+	assert.Equal(t, 102, parser.GetCurrentBlock())
+	mockStorage.AssertExpectations(t)
+}
+
+func TestParser_StartParsing_StatusUpdateError(t *testing.T) {
+	mockClient := mocks.NewEthereumClient(t)
+	mockStorage := storagemocks.NewStorage(t)
+	parser := ethereum.New(99, mockClient, mockStorage, ethereum.WithConfirmationDepth(2))
+
+	subscribed := model.Address("0xSubscribedAddress")
+	tx := model.Transaction{Hash: "0xHash100", From: "0xAddress2", To: subscribed, BlockNumber: 100}
+
+	mockStorage.On("IsSubscribed", mock.Anything, subscribed).Return(true, nil)
+	mockStorage.On("IsSubscribed", mock.Anything, mock.Anything).Return(false, nil)
+	mockClient.On("GetBlockNumberByTag", mock.Anything, model.BlockTagFinalized).Return(uint64(90), nil)
+
+	mockClient.On("GetLatestBlockNumber", mock.Anything).Return(uint64(100), nil).Once()
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(100)).Return(model.Block{Number: 100, Hash: "0xBlock100", ParentHash: "0xBlock99", Transactions: []model.Transaction{tx}}, nil).Once()
+	mockStorage.On("CommitBatch", mock.Anything, transactionBatch(subscribed, withStatus(tx, model.TransactionStatusPending))).Return(nil).Once()
+
+	assert.NoError(t, parser.StartParsing(context.Background()))
+
+	// Confirming block 100 fails the first time.
+	mockClient.On("GetLatestBlockNumber", mock.Anything).Return(uint64(102), nil).Once()
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(101)).Return(model.Block{Number: 101, Hash: "0xBlock101", ParentHash: "0xBlock100"}, nil).Once()
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(102)).Return(model.Block{Number: 102, Hash: "0xBlock102", ParentHash: "0xBlock101"}, nil).Once()
+
+	mockError := errors.New("disk full")
+	mockStorage.On("CommitBatch", mock.Anything, statusBatch(subscribed, model.TransactionStatusConfirmed, "0xHash100")).Return(mockError).Once()
+
+	assert.ErrorIs(t, parser.StartParsing(context.Background()), mockError)
+
+	// The next run retries it.
+	mockClient.On("GetLatestBlockNumber", mock.Anything).Return(uint64(103), nil).Once()
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(103)).Return(model.Block{Number: 103, Hash: "0xBlock103", ParentHash: "0xBlock102"}, nil).Once()
+	mockStorage.On("CommitBatch", mock.Anything, statusBatch(subscribed, model.TransactionStatusConfirmed, "0xHash100")).Return(nil).Once()
+
+	assert.NoError(t, parser.StartParsing(context.Background()))
+	mockStorage.AssertExpectations(t)
+}
+
+func TestParser_StartParsing_FollowTag(t *testing.T) {
+	mockClient := mocks.NewEthereumClient(t)
+	parser := ethereum.New(90, mockClient, nil, ethereum.WithFollowTag(model.BlockTagSafe))
+
+	mockClient.On("GetLatestBlockNumber", mock.Anything).Return(uint64(100), nil)
+	mockClient.On("GetBlockNumberByTag", mock.Anything, model.BlockTagSafe).Return(uint64(91), nil)
+	mockClient.On("GetBlockNumberByTag", mock.Anything, model.BlockTagFinalized).Return(uint64(80), nil)
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(91)).Return(model.Block{Number: 91, Hash: "0xBlock91", ParentHash: "0xBlock90"}, nil)
+
+	err := parser.StartParsing(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 91, parser.GetCurrentBlock(), "currentBlock should stop at the safe block")
+}
+
+func withStatus(tx model.Transaction, status model.TransactionStatus) model.Transaction {
+	tx.Status = status
+	return tx
+}
+
+func TestParser_StartParsing_ResumeFromCheckpoint(t *testing.T) {
+	mockClient := mocks.NewEthereumClient(t)
+	mockCheckpoints := storagemocks.NewCheckpointStore(t)
+	parser := ethereum.New(0, mockClient, nil, ethereum.WithCheckpointStore(mockCheckpoints))
+
+	mockCheckpoints.On("LoadCheckpoint", mock.Anything).Return(model.Checkpoint{BlockNumber: 98, BlockHash: "0xBlock98"}, nil).Once()
+
+	mockClient.On("GetLatestBlockNumber", mock.Anything).Return(uint64(100), nil)
+	mockClient.On("GetBlockNumberByTag", mock.Anything, model.BlockTagFinalized).Return(uint64(50), nil)
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(99)).Return(model.Block{Number: 99, Hash: "0xBlock99", ParentHash: "0xBlock98"}, nil)
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(100)).Return(model.Block{Number: 100, Hash: "0xBlock100", ParentHash: "0xBlock99"}, nil)
+
+	resumed := pendingBlock(98, "0xBlock98", "")
+	mockCheckpoints.On("SaveCheckpoint", mock.Anything, model.Checkpoint{BlockNumber: 99, BlockHash: "0xBlock99", Pending: []model.PendingBlock{
+		resumed,
+		pendingBlock(99, "0xBlock99", "0xBlock98"),
+	}}).Return(nil).Once()
+	mockCheckpoints.On("SaveCheckpoint", mock.Anything, model.Checkpoint{BlockNumber: 100, BlockHash: "0xBlock100", Pending: []model.PendingBlock{
+		resumed,
+		pendingBlock(99, "0xBlock99", "0xBlock98"),
+		pendingBlock(100, "0xBlock100", "0xBlock99"),
+	}}).Return(nil).Once()
+
+	err := parser.StartParsing(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 100, parser.GetCurrentBlock(), "parser should resume after the checkpoint instead of jumping to head")
+}
+
+func TestParser_StartParsing_NoCheckpoint(t *testing.T) {
+	mockClient := mocks.NewEthereumClient(t)
+	mockCheckpoints := storagemocks.NewCheckpointStore(t)
+	parser := ethereum.New(0, mockClient, nil, ethereum.WithCheckpointStore(mockCheckpoints))
+
+	mockCheckpoints.On("LoadCheckpoint", mock.Anything).Return(model.Checkpoint{}, storage.ErrNoCheckpoint).Once()
+	mockClient.On("GetLatestBlockNumber", mock.Anything).Return(uint64(100), nil)
+
+	assert.NoError(t, parser.StartParsing(context.Background()))
+	assert.NoError(t, parser.StartParsing(context.Background()))
+	assert.Equal(t, 100, parser.GetCurrentBlock(), "parser should start at head without a checkpoint")
+}
+
+func TestParser_StartParsing_CheckpointError(t *testing.T) {
+	mockClient := mocks.NewEthereumClient(t)
+	mockCheckpoints := storagemocks.NewCheckpointStore(t)
+	parser := ethereum.New(98, mockClient, nil, ethereum.WithCheckpointStore(mockCheckpoints))
+
+	mockCheckpoints.On("LoadCheckpoint", mock.Anything).Return(model.Checkpoint{}, storage.ErrNoCheckpoint)
+	mockClient.On("GetLatestBlockNumber", mock.Anything).Return(uint64(100), nil)
+	mockClient.On("GetBlockNumberByTag", mock.Anything, model.BlockTagFinalized).Return(uint64(50), nil)
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(99)).Return(model.Block{Number: 99, Hash: "0xBlock99", ParentHash: "0xBlock98"}, nil)
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(100)).Return(model.Block{Number: 100, Hash: "0xBlock100", ParentHash: "0xBlock99"}, nil).Maybe()
+
+	mockError := errors.New("disk full")
+	mockCheckpoints.On("SaveCheckpoint", mock.Anything, mock.Anything).Return(mockError)
+
+	err := parser.StartParsing(context.Background())
+
+	assert.ErrorIs(t, err, mockError)
+}
+
+func TestParser_StartParsing_CommitError(t *testing.T) {
+	mockClient := mocks.NewEthereumClient(t)
+	mockStorage := storagemocks.NewStorage(t)
+	mockCheckpoints := storagemocks.NewCheckpointStore(t)
+	parser := ethereum.New(98, mockClient, mockStorage, ethereum.WithCheckpointStore(mockCheckpoints))
+
+	subscribed := model.Address("0xSubscribedAddress")
+	tx := model.Transaction{Hash: "0xHash99", From: subscribed, To: "0xAddress2", BlockNumber: 99}
+
+	mockCheckpoints.On("LoadCheckpoint", mock.Anything).Return(model.Checkpoint{}, storage.ErrNoCheckpoint)
+	mockClient.On("GetLatestBlockNumber", mock.Anything).Return(uint64(100), nil)
+	mockClient.On("GetBlockNumberByTag", mock.Anything, model.BlockTagFinalized).Return(uint64(50), nil)
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(99)).Return(model.Block{Number: 99, Hash: "0xBlock99", ParentHash: "0xBlock98", Transactions: []model.Transaction{tx}}, nil)
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(100)).Return(model.Block{Number: 100, Hash: "0xBlock100", ParentHash: "0xBlock99"}, nil).Maybe()
+	mockStorage.On("IsSubscribed", mock.Anything, subscribed).Return(true, nil)
+	mockStorage.On("IsSubscribed", mock.Anything, mock.Anything).Return(false, nil)
+
+	mockError := errors.New("disk full")
+	mockStorage.On("CommitBatch", mock.Anything, transactionBatch(subscribed, withStatus(tx, model.TransactionStatusPending))).Return(mockError).Once()
+
+	err := parser.StartParsing(context.Background())
+
+	assert.ErrorIs(t, err, mockError)
+	assert.Equal(t, 98, parser.GetCurrentBlock(), "a block that failed to commit should be parsed again")
+	mockCheckpoints.AssertNotCalled(t, "SaveCheckpoint", mock.Anything, mock.Anything)
+}
+
+func TestParser_StartParsing_CheckpointInStorage(t *testing.T) {
+	mockClient := mocks.NewEthereumClient(t)
+	store := inmem.New()
+	parser := ethereum.New(99, mockClient, store, ethereum.WithCheckpointStore(store))
+
+	subscribed := model.Address("0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed")
+	tx := model.Transaction{Hash: "0xHash100", From: "0xfb6916095ca1df60bb79ce92ce3ea74c37c5d359", To: subscribed, BlockNumber: 100}
+	assert.NoError(t, parser.Subscribe(string(subscribed)))
+
+	mockClient.On("GetLatestBlockNumber", mock.Anything).Return(uint64(100), nil)
+	mockClient.On("GetBlockNumberByTag", mock.Anything, model.BlockTagFinalized).Return(uint64(50), nil)
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(100)).Return(model.Block{Number: 100, Hash: "0xBlock100", ParentHash: "0xBlock99", Transactions: []model.Transaction{tx}}, nil)
+
+	assert.NoError(t, parser.StartParsing(context.Background()))
+
+	checkpoint, err := store.LoadCheckpoint(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, model.Checkpoint{BlockNumber: 100, BlockHash: "0xBlock100", Pending: []model.PendingBlock{
+		pendingBlock(100, "0xBlock100", "0xBlock99", model.ActivityKey{Address: subscribed, Hash: tx.Hash}),
+	}}, checkpoint)
+	assert.Equal(t, []model.Transaction{withStatus(tx, model.TransactionStatusPending)}, parser.GetTransactions(string(subscribed)))
+}
+
+func TestParser_StartParsing_ConcurrentFetchCommitsInOrder(t *testing.T) {
+	mockClient := mocks.NewEthereumClient(t)
+	mockStorage := storagemocks.NewStorage(t)
+	parser := ethereum.New(100, mockClient, mockStorage, ethereum.WithFetchConcurrency(8), ethereum.WithFetchWindow(4))
+
+	subscribed := model.Address("0xSubscribedAddress")
+
+	mockClient.On("GetLatestBlockNumber", mock.Anything).Return(uint64(140), nil)
+	mockClient.On("GetBlockNumberByTag", mock.Anything, model.BlockTagFinalized).Return(uint64(50), nil)
+	mockStorage.On("IsSubscribed", mock.Anything, subscribed).Return(true, nil)
+	mockStorage.On("IsSubscribed", mock.Anything, mock.Anything).Return(false, nil)
+
+	for number := uint64(101); number <= 140; number++ {
+		tx := model.Transaction{Hash: fmt.Sprintf("0xHash%d", number), From: subscribed, To: "0xAddress2"}
+		block := model.Block{
+			Number:       number,
+			Hash:         fmt.Sprintf("0xBlock%d", number),
+			ParentHash:   fmt.Sprintf("0xBlock%d", number-1),
+			Transactions: []model.Transaction{tx},
+		}
+
+		// Earlier blocks answer slower, so fetches complete out of order.
+		mockClient.On("GetBlockByNumber", mock.Anything, number).After(time.Duration(140-number)*100*time.Microsecond).Return(block, nil).Once()
+	}
+
+	var committed []string
+	mockStorage.On("CommitBatch", mock.Anything, mock.AnythingOfType("storage.Batch")).Run(func(args mock.Arguments) {
+		for _, entry := range args.Get(1).(storage.Batch).Transactions {
+			committed = append(committed, entry.Transaction.Hash)
+		}
+	}).Return(nil)
+
+	err := parser.StartParsing(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 140, parser.GetCurrentBlock())
+	assert.Len(t, committed, 40)
+	for i, hash := range committed {
+		assert.Equal(t, fmt.Sprintf("0xHash%d", 101+i), hash, "blocks should be committed in order")
+	}
+}
+
+func TestParser_StartParsing_FetchErrorStopsAtFailedBlock(t *testing.T) {
+	mockClient := mocks.NewEthereumClient(t)
+	parser := ethereum.New(100, mockClient, nil, ethereum.WithFetchConcurrency(4))
+
+	mockClient.On("GetLatestBlockNumber", mock.Anything).Return(uint64(110), nil)
+	mockClient.On("GetBlockNumberByTag", mock.Anything, model.BlockTagFinalized).Return(uint64(50), nil)
+
+	mockError := errors.New("client error")
+	for number := uint64(101); number <= 110; number++ {
+		if number == 104 {
+			mockClient.On("GetBlockByNumber", mock.Anything, number).Return(model.Block{}, mockError).Once()
+			continue
+		}
+
+		block := model.Block{Number: number, Hash: fmt.Sprintf("0xBlock%d", number), ParentHash: fmt.Sprintf("0xBlock%d", number-1)}
+		mockClient.On("GetBlockByNumber", mock.Anything, number).Return(block, nil).Maybe()
+	}
+
+	err := parser.StartParsing(context.Background())
+
+	assert.ErrorIs(t, err, mockError)
+	assert.Equal(t, 103, parser.GetCurrentBlock(), "currentBlock must not advance past the failed block")
+}
+
+func TestParser_StartParsing_CancelStopsBetweenBlocks(t *testing.T) {
+	mockClient := mocks.NewEthereumClient(t)
+	mockStorage := storagemocks.NewStorage(t)
+	parser := ethereum.New(100, mockClient, mockStorage, ethereum.WithFetchConcurrency(1))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mockClient.On("GetLatestBlockNumber", mock.Anything).Return(uint64(105), nil)
+	mockClient.On("GetBlockNumberByTag", mock.Anything, model.BlockTagFinalized).Return(uint64(50), nil)
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(101)).Return(model.Block{
+		Number:       101,
+		Hash:         "0xBlock101",
+		Transactions: []model.Transaction{{Hash: "0xHash101", From: "0xSubscribedAddress", To: "0xAddress2"}},
+	}, nil)
+	mockClient.On("GetBlockByNumber", mock.Anything, mock.Anything).Return(model.Block{}, nil).Maybe()
+
+	mockStorage.On("IsSubscribed", mock.Anything, model.Address("0xSubscribedAddress")).Return(true, nil)
+	mockStorage.On("IsSubscribed", mock.Anything, mock.Anything).Return(false, nil)
+	mockStorage.On("CommitBatch", mock.Anything, mock.Anything).
+		Run(func(mock.Arguments) { cancel() }).
+		Return(nil).Once()
+
+	err := parser.StartParsing(ctx)
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 101, parser.GetCurrentBlock(), "the block being committed should finish, the rest should not start")
+}
+
+func TestParser_StartParsing_CancelDuringFetch(t *testing.T) {
+	mockClient := mocks.NewEthereumClient(t)
+	parser := ethereum.New(100, mockClient, nil, ethereum.WithFetchConcurrency(1))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mockClient.On("GetLatestBlockNumber", mock.Anything).Return(uint64(105), nil)
+	mockClient.On("GetBlockNumberByTag", mock.Anything, model.BlockTagFinalized).Return(uint64(50), nil)
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(101)).
+		Run(func(mock.Arguments) { cancel() }).
+		Return(model.Block{Number: 101, Hash: "0xBlock101"}, nil).Once()
+	mockClient.On("GetBlockByNumber", mock.Anything, mock.Anything).Return(model.Block{}, nil).Maybe()
+
+	err := parser.StartParsing(ctx)
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.LessOrEqual(t, parser.GetCurrentBlock(), 101)
+	mockClient.AssertExpectations(t)
+}
+
+func TestParser_StartParsing_Receipts(t *testing.T) {
+	mockClient := mocks.NewEthereumClient(t)
+	mockStorage := storagemocks.NewStorage(t)
+	parser := ethereum.New(100, mockClient, mockStorage, ethereum.WithReceipts())
+
+	mockClient.On("GetLatestBlockNumber", mock.Anything).Return(uint64(102), nil)
+	mockClient.On("GetBlockNumberByTag", mock.Anything, model.BlockTagFinalized).Return(uint64(50), nil)
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(101)).Return(model.Block{
+		Number: 101,
+		Hash:   "0xBlock101",
+		Transactions: []model.Transaction{
+			{Hash: "0xHash101", From: "0xSubscribedAddress", To: "0xAddress2"},
+			{Hash: "0xOther", From: "0xAddress3", To: "0xAddress4"},
+		},
+	}, nil)
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(102)).Return(model.Block{
+		Number:       102,
+		Hash:         "0xBlock102",
+		ParentHash:   "0xBlock101",
+		Transactions: []model.Transaction{{Hash: "0xHash102", From: "0xAddress3", To: "0xAddress4"}},
+	}, nil)
+
+	receipt := model.Receipt{TransactionHash: "0xHash101", Status: model.ReceiptStatusSuccess, GasUsed: 21000}
+	mockClient.On("GetBlockReceipts", mock.Anything, uint64(101)).Return([]model.Receipt{
+		receipt,
+		{TransactionHash: "0xOther", Status: model.ReceiptStatusFailed},
+	}, nil).Once()
+
+	mockStorage.On("IsSubscribed", mock.Anything, model.Address("0xSubscribedAddress")).Return(true, nil)
+	mockStorage.On("IsSubscribed", mock.Anything, mock.Anything).Return(false, nil)
+	mockStorage.On("CommitBatch", mock.Anything, transactionBatch("0xSubscribedAddress", model.Transaction{
+		Hash:    "0xHash101",
+		From:    "0xSubscribedAddress",
+		To:      "0xAddress2",
+		Status:  model.TransactionStatusPending,
+		Receipt: &receipt,
+	})).Return(nil).Once()
+
+	assert.NoError(t, parser.StartParsing(context.Background()))
+	assert.Equal(t, 102, parser.GetCurrentBlock())
+}
+
+func TestParser_StartParsing_ReceiptsSubscriptionError(t *testing.T) {
+	mockClient := mocks.NewEthereumClient(t)
+	mockStorage := storagemocks.NewStorage(t)
+	parser := ethereum.New(100, mockClient, mockStorage, ethereum.WithReceipts())
+
+	mockClient.On("GetLatestBlockNumber", mock.Anything).Return(uint64(101), nil)
+	mockClient.On("GetBlockNumberByTag", mock.Anything, model.BlockTagFinalized).Return(uint64(50), nil)
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(101)).Return(model.Block{
+		Number:       101,
+		Hash:         "0xBlock101",
+		Transactions: []model.Transaction{{Hash: "0xHash101", From: "0xSubscribedAddress", To: "0xAddress2"}},
+	}, nil)
+
+	storageErr := errors.New("storage unavailable")
+	// Only the receipt check fails; the block must not be committed without
+	// the receipts it may need.
+	mockStorage.On("IsSubscribed", mock.Anything, mock.Anything).Return(false, storageErr).Once()
+	mockStorage.On("IsSubscribed", mock.Anything, mock.Anything).Return(true, nil).Maybe()
+	mockClient.On("GetBlockReceipts", mock.Anything, mock.Anything).Return([]model.Receipt{}, nil).Maybe()
+	mockStorage.On("CommitBatch", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	assert.ErrorIs(t, parser.StartParsing(context.Background()), storageErr)
+	assert.Equal(t, 100, parser.GetCurrentBlock())
+	mockStorage.AssertNotCalled(t, "CommitBatch", mock.Anything, mock.Anything)
+}
+
+func TestParser_StartParsing_ContractCreation(t *testing.T) {
+	mockClient := mocks.NewEthereumClient(t)
+	mockStorage := storagemocks.NewStorage(t)
+	parser := ethereum.New(100, mockClient, mockStorage, ethereum.WithReceipts())
+
+	deployed := model.Address("0xDeployedContract")
+	creation := model.Transaction{Hash: "0xCreation", From: "0xDeployer", ContractAddress: "0xComputedFromNonce"}
+
+	mockClient.On("GetLatestBlockNumber", mock.Anything).Return(uint64(101), nil)
+	mockClient.On("GetBlockNumberByTag", mock.Anything, model.BlockTagFinalized).Return(uint64(50), nil)
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(101)).Return(model.Block{
+		Number:       101,
+		Hash:         "0xBlock101",
+		Transactions: []model.Transaction{creation},
+	}, nil)
+
+	receipt := model.Receipt{TransactionHash: "0xCreation", Status: model.ReceiptStatusSuccess, ContractAddress: deployed}
+	mockClient.On("GetBlockReceipts", mock.Anything, uint64(101)).Return([]model.Receipt{receipt}, nil).Once()
+
+	// The computed address is what decides whether receipts are fetched; the
+	// receipt's address is what the transaction is then stored under.
+	mockStorage.On("IsSubscribed", mock.Anything, model.Address("0xDeployer")).Return(false, nil)
+	mockStorage.On("IsSubscribed", mock.Anything, model.Address("0xComputedFromNonce")).Return(true, nil).Once()
+	mockStorage.On("IsSubscribed", mock.Anything, deployed).Return(true, nil).Once()
+
+	stored := creation
+	stored.ContractAddress = deployed
+	stored.Receipt = &receipt
+	mockStorage.On("CommitBatch", mock.Anything, transactionBatch(deployed, withStatus(stored, model.TransactionStatusPending))).Return(nil).Once()
+
+	assert.NoError(t, parser.StartParsing(context.Background()))
+	mockStorage.AssertNotCalled(t, "IsSubscribed", mock.Anything, model.Address(""))
+}
+
+func TestParser_StartParsing_TokenTransfers(t *testing.T) {
+	mockClient := mocks.NewEthereumClient(t)
+	mockStorage := storagemocks.NewStorage(t)
+	parser := ethereum.New(100, mockClient, mockStorage, ethereum.WithTokenTransfers())
+
+	subscribed := model.Address("0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed")
+	sender := model.Address("0xfb6916095ca1df60bb79ce92ce3ea74c37c5d359")
+	token := model.Address("0xdac17f958d2ee523a2206206994597c13d831ec7")
+
+	mockClient.On("GetLatestBlockNumber", mock.Anything).Return(uint64(101), nil)
+	mockClient.On("GetBlockNumberByTag", mock.Anything, model.BlockTagFinalized).Return(uint64(50), nil)
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(101)).Return(model.Block{Number: 101, Hash: "0xBlock101"}, nil)
+	mockClient.On("GetLogs", mock.Anything, uint64(101), []string{ethereum.TransferTopic}).Return([]model.Log{
+		{
+			Address:         token,
+			Topics:          []string{ethereum.TransferTopic, addressTopic(sender), addressTopic(subscribed)},
+			Data:            "0x00000000000000000000000000000000000000000000000000000000000f4240",
+			TransactionHash: "0xTxHash1",
+			LogIndex:        3,
+		},
+		{
+			// An ERC-721 transfer: the token id is indexed, so there is a fourth topic.
+			Address:         "0x06012c8cf97bead5deae237070f9587f8e7a266d",
+			Topics:          []string{ethereum.TransferTopic, addressTopic(sender), addressTopic(subscribed), "0x0000000000000000000000000000000000000000000000000000000000000001"},
+			TransactionHash: "0xTxHash2",
+			LogIndex:        4,
+		},
+	}, nil)
+
+	mockStorage.On("IsSubscribed", mock.Anything, subscribed).Return(true, nil)
+	mockStorage.On("IsSubscribed", mock.Anything, sender).Return(false, nil)
+	mockStorage.On("CommitBatch", mock.Anything, storage.Batch{TokenTransfers: []storage.AddressTokenTransfer{
+		{Address: subscribed, Transfer: model.TokenTransfer{
+			Standard:        model.TokenStandardERC20,
+			Token:           token,
+			From:            sender,
+			To:              subscribed,
+			Amount:          big.NewInt(1_000_000),
+			TransactionHash: "0xTxHash1",
+			LogIndex:        3,
+			BlockNumber:     101,
+			Status:          model.TransactionStatusPending,
+		}},
+	}}).Return(nil).Once()
+
+	assert.NoError(t, parser.StartParsing(context.Background()))
+}
+
+// TestParser_StartParsing_MalformedTransferBatch feeds TransferBatch logs whose
+// array offset and length point outside their data, which any contract can
+// emit; they are skipped instead of crashing the parser.
+func TestParser_StartParsing_MalformedTransferBatch(t *testing.T) {
+	mockClient := mocks.NewEthereumClient(t)
+	mockStorage := storagemocks.NewStorage(t)
+	parser := ethereum.New(100, mockClient, mockStorage, ethereum.WithNFTTransfers())
+
+	subscribed := model.Address("0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed")
+	sender := model.Address("0xfb6916095ca1df60bb79ce92ce3ea74c37c5d359")
+	operator := model.Address("0xdbf03b407c01e7cd3cbea99509d93f8dddc8c6fb")
+	items := model.Address("0xd1220a0cf47c7b9be7a2e6ba89f429762e7b9adb")
+	topics := []string{ethereum.TransferBatchTopic, addressTopic(operator), addressTopic(sender), addressTopic(subscribed)}
+
+	mockClient.On("GetLatestBlockNumber", mock.Anything).Return(uint64(101), nil)
+	mockClient.On("GetBlockNumberByTag", mock.Anything, model.BlockTagFinalized).Return(uint64(50), nil)
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(101)).Return(model.Block{Number: 101, Hash: "0xBlock101"}, nil)
+	mockClient.On("GetLogs", mock.Anything, uint64(101), []string{ethereum.TransferTopic, ethereum.TransferSingleTopic, ethereum.TransferBatchTopic}).Return([]model.Log{
+		{
+			// The ids offset is 2^63-32, far past the end of the data.
+			Address: items,
+			Topics:  topics,
+			Data: "0x" +
+				"0000000000000000000000000000000000000000000000007fffffffffffffe0" +
+				"0000000000000000000000000000000000000000000000000000000000000040",
+			TransactionHash: "0xTxHash1",
+			LogIndex:        1,
+		},
+		{
+			// The ids array claims two entries but the data ends after one.
+			Address: items,
+			Topics:  topics,
+			Data: "0x" +
+				"0000000000000000000000000000000000000000000000000000000000000040" +
+				"0000000000000000000000000000000000000000000000000000000000000040" +
+				"0000000000000000000000000000000000000000000000000000000000000002" +
+				"0000000000000000000000000000000000000000000000000000000000000001",
+			TransactionHash: "0xTxHash2",
+			LogIndex:        2,
+		},
+	}, nil)
+
+	mockStorage.On("IsSubscribed", mock.Anything, mock.Anything).Return(true, nil).Maybe()
+	mockClient.On("GetBlockReceipts", mock.Anything, mock.Anything).Return([]model.Receipt{}, nil).Maybe()
+	mockStorage.On("CommitBatch", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	assert.NoError(t, parser.StartParsing(context.Background()))
+	assert.Equal(t, 101, parser.GetCurrentBlock())
+}
+
+func TestParser_StartParsing_NFTTransfers(t *testing.T) {
+	mockClient := mocks.NewEthereumClient(t)
+	mockStorage := storagemocks.NewStorage(t)
+	parser := ethereum.New(100, mockClient, mockStorage, ethereum.WithNFTTransfers())
+
+	subscribed := model.Address("0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed")
+	sender := model.Address("0xfb6916095ca1df60bb79ce92ce3ea74c37c5d359")
+	operator := model.Address("0xdbf03b407c01e7cd3cbea99509d93f8dddc8c6fb")
+	kitties := model.Address("0x06012c8cf97bead5deae237070f9587f8e7a266d")
+	items := model.Address("0xd1220a0cf47c7b9be7a2e6ba89f429762e7b9adb")
+
+	mockClient.On("GetLatestBlockNumber", mock.Anything).Return(uint64(101), nil)
+	mockClient.On("GetBlockNumberByTag", mock.Anything, model.BlockTagFinalized).Return(uint64(50), nil)
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(101)).Return(model.Block{Number: 101, Hash: "0xBlock101"}, nil)
+	mockClient.On("GetLogs", mock.Anything, uint64(101), []string{ethereum.TransferTopic, ethereum.TransferSingleTopic, ethereum.TransferBatchTopic}).Return([]model.Log{
+		{
+			// An ERC-20 transfer is not tracked without WithTokenTransfers.
+			Address:         "0xdac17f958d2ee523a2206206994597c13d831ec7",
+			Topics:          []string{ethereum.TransferTopic, addressTopic(sender), addressTopic(subscribed)},
+			Data:            "0x00000000000000000000000000000000000000000000000000000000000f4240",
+			TransactionHash: "0xTxHash1",
+			LogIndex:        1,
+		},
+		{
+			Address:         kitties,
+			Topics:          []string{ethereum.TransferTopic, addressTopic(sender), addressTopic(subscribed), "0x000000000000000000000000000000000000000000000000000000000000002a"},
+			TransactionHash: "0xTxHash2",
+			LogIndex:        2,
+		},
+		{
+			Address: items,
+			Topics:  []string{ethereum.TransferSingleTopic, addressTopic(operator), addressTopic(subscribed), addressTopic(sender)},
+			Data: "0x" +
+				"0000000000000000000000000000000000000000000000000000000000000007" +
+				"0000000000000000000000000000000000000000000000000000000000000005",
+			TransactionHash: "0xTxHash3",
+			LogIndex:        3,
+		},
+		{
+			Address: items,
+			Topics:  []string{ethereum.TransferBatchTopic, addressTopic(operator), addressTopic(sender), addressTopic(subscribed)},
+			Data: "0x" +
+				"0000000000000000000000000000000000000000000000000000000000000040" +
+				"00000000000000000000000000000000000000000000000000000000000000a0" +
+				"0000000000000000000000000000000000000000000000000000000000000002" +
+				"0000000000000000000000000000000000000000000000000000000000000001" +
+				"0000000000000000000000000000000000000000000000000000000000000002" +
+				"0000000000000000000000000000000000000000000000000000000000000002" +
+				"000000000000000000000000000000000000000000000000000000000000000a" +
+				"0000000000000000000000000000000000000000000000000000000000000014",
+			TransactionHash: "0xTxHash4",
+			LogIndex:        4,
+		},
+	}, nil)
+
+	mockStorage.On("IsSubscribed", mock.Anything, subscribed).Return(true, nil)
+	mockStorage.On("IsSubscribed", mock.Anything, sender).Return(false, nil)
+	mockStorage.On("CommitBatch", mock.Anything, storage.Batch{TokenTransfers: []storage.AddressTokenTransfer{
+		{Address: subscribed, Transfer: model.TokenTransfer{
+			Standard:        model.TokenStandardERC721,
+			Token:           kitties,
+			From:            sender,
+			To:              subscribed,
+			TokenID:         big.NewInt(42),
+			Amount:          big.NewInt(1),
+			TransactionHash: "0xTxHash2",
+			LogIndex:        2,
+			BlockNumber:     101,
+			Status:          model.TransactionStatusPending,
+		}},
+		{Address: subscribed, Transfer: model.TokenTransfer{
+			Standard:        model.TokenStandardERC1155,
+			Token:           items,
+			Operator:        operator,
+			From:            subscribed,
+			To:              sender,
+			TokenID:         big.NewInt(7),
+			Amount:          big.NewInt(5),
+			TransactionHash: "0xTxHash3",
+			LogIndex:        3,
+			BlockNumber:     101,
+			Status:          model.TransactionStatusPending,
+		}},
+		{Address: subscribed, Transfer: model.TokenTransfer{
+			Standard: model.TokenStandardERC1155,
+			Token:    items,
+			Operator: operator,
+			From:     sender,
+			To:       subscribed,
+			Batch: []model.TokenAmount{
+				{TokenID: big.NewInt(1), Amount: big.NewInt(10)},
+				{TokenID: big.NewInt(2), Amount: big.NewInt(20)},
+			},
+			TransactionHash: "0xTxHash4",
+			LogIndex:        4,
+			BlockNumber:     101,
+			Status:          model.TransactionStatusPending,
+		}},
+	}}).Return(nil).Once()
+
+	assert.NoError(t, parser.StartParsing(context.Background()))
+}
+
+func TestParser_GetNFTTransfers(t *testing.T) {
+	mockStorage := storagemocks.NewStorage(t)
+	parser := ethereum.New(0, nil, mockStorage)
+
+	address := model.Address("0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed")
+	fungible := model.TokenTransfer{Standard: model.TokenStandardERC20, TransactionHash: "0xTxHash1"}
+	nft := model.TokenTransfer{Standard: model.TokenStandardERC721, TransactionHash: "0xTxHash2"}
+	mockStorage.On("GetTokenTransfers", mock.Anything, address).Return([]model.TokenTransfer{fungible, nft}, nil)
+
+	assert.Equal(t, []model.TokenTransfer{nft}, parser.GetNFTTransfers(string(address)))
+	assert.Equal(t, []model.TokenTransfer{fungible}, parser.GetTokenTransfers(string(address)))
+}
+
+func TestParser_StartParsing_InternalTransfers(t *testing.T) {
+	mockClient := mocks.NewEthereumClient(t)
+	mockStorage := storagemocks.NewStorage(t)
+	parser := ethereum.New(100, mockClient, mockStorage, ethereum.WithInternalTransfers(model.TraceAPIDebug))
+
+	subscribed := model.Address("0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed")
+	sender := model.Address("0xfb6916095ca1df60bb79ce92ce3ea74c37c5d359")
+	multisig := model.Address("0xdbf03b407c01e7cd3cbea99509d93f8dddc8c6fb")
+
+	tx := model.Transaction{Hash: "0xTxHash1", From: sender, To: multisig, BlockNumber: 101}
+	mockClient.On("GetLatestBlockNumber", mock.Anything).Return(uint64(101), nil)
+	mockClient.On("GetBlockNumberByTag", mock.Anything, model.BlockTagFinalized).Return(uint64(50), nil)
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(101)).Return(model.Block{Number: 101, Hash: "0xBlock101", Transactions: []model.Transaction{tx}}, nil)
+	mockClient.On("GetInternalTransfers", mock.Anything, uint64(101), model.TraceAPIDebug).Return([]model.InternalTransfer{
+		// The node left out the transaction hash, so it is taken from the block.
+		{TransactionIndex: 0, Index: 1, Type: "call", From: multisig, To: subscribed, Value: big.NewInt(1_000)},
+	}, nil)
+
+	mockStorage.On("IsSubscribed", mock.Anything, sender).Return(false, nil)
+	mockStorage.On("IsSubscribed", mock.Anything, multisig).Return(false, nil)
+	mockStorage.On("IsSubscribed", mock.Anything, subscribed).Return(true, nil)
+	mockStorage.On("CommitBatch", mock.Anything, storage.Batch{InternalTransfers: []storage.AddressInternalTransfer{
+		{Address: subscribed, Transfer: model.InternalTransfer{
+			TransactionHash: "0xTxHash1",
+			Index:           1,
+			Type:            "call",
+			From:            multisig,
+			To:              subscribed,
+			Value:           big.NewInt(1_000),
+			BlockNumber:     101,
+			Status:          model.TransactionStatusPending,
+		}},
+	}}).Return(nil).Once()
+
+	assert.NoError(t, parser.StartParsing(context.Background()))
+}
+
+func TestParser_GetInternalTransfers(t *testing.T) {
+	mockStorage := storagemocks.NewStorage(t)
+	parser := ethereum.New(0, nil, mockStorage)
+
+	address := model.Address("0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed")
+	transfers := []model.InternalTransfer{{TransactionHash: "0xTxHash1", Index: 1, To: address, Value: big.NewInt(1)}}
+	mockStorage.On("GetInternalTransfers", mock.Anything, address).Return(transfers, nil)
+
+	assert.Equal(t, transfers, parser.GetInternalTransfers("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"))
+	assert.Nil(t, parser.GetInternalTransfers("not an address"))
+}
+
+func TestParser_StartParsing_BloomFilter(t *testing.T) {
+	mockClient := mocks.NewEthereumClient(t)
+	mockStorage := storagemocks.NewStorage(t)
+	parser := ethereum.New(100, mockClient, mockStorage, ethereum.WithTokenTransfers(), ethereum.WithBloomFilter())
+
+	subscribed := model.Address("0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed")
+	sender := model.Address("0xfb6916095ca1df60bb79ce92ce3ea74c37c5d359")
+	token := model.Address("0xdac17f958d2ee523a2206206994597c13d831ec7")
+
+	topic, _ := hex.DecodeString(strings.TrimPrefix(addressTopic(subscribed), "0x"))
+	matching := &model.Bloom{}
+	matching.Add(topic)
+
+	mockClient.On("GetLatestBlockNumber", mock.Anything).Return(uint64(102), nil)
+	mockClient.On("GetBlockNumberByTag", mock.Anything, model.BlockTagFinalized).Return(uint64(50), nil)
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(101)).Return(model.Block{Number: 101, Hash: "0xBlock101", LogsBloom: matching}, nil)
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(102)).Return(model.Block{Number: 102, Hash: "0xBlock102", ParentHash: "0xBlock101", LogsBloom: &model.Bloom{}}, nil)
+	mockClient.On("GetLogs", mock.Anything, uint64(101), []string{ethereum.TransferTopic}).Return([]model.Log{
+		{
+			Address:         token,
+			Topics:          []string{ethereum.TransferTopic, addressTopic(sender), addressTopic(subscribed)},
+			Data:            "0x0000000000000000000000000000000000000000000000000000000000000001",
+			TransactionHash: "0xTxHash1",
+			LogIndex:        0,
+		},
+	}, nil).Once()
+
+	mockStorage.On("ListAddresses", mock.Anything).Return([]model.Address{subscribed}, nil)
+	mockStorage.On("IsSubscribed", mock.Anything, subscribed).Return(true, nil)
+	mockStorage.On("IsSubscribed", mock.Anything, sender).Return(false, nil)
+	mockStorage.On("CommitBatch", mock.Anything, mock.Anything).Return(nil).Once()
+
+	assert.NoError(t, parser.StartParsing(context.Background()))
+	mockClient.AssertNotCalled(t, "GetLogs", mock.Anything, uint64(102), mock.Anything)
+	assert.Equal(t, ethereum.BloomStats{Checked: 2, Skipped: 1}, parser.BloomStats())
+}
+
+func transactionBatch(address model.Address, txs ...model.Transaction) storage.Batch {
+	var batch storage.Batch
+	for _, tx := range txs {
+		batch.AddTransaction(address, tx)
+	}
+
+	return batch
+}
+
+func pendingBlock(number uint64, hash, parentHash string, txs ...model.ActivityKey) model.PendingBlock {
+	return model.PendingBlock{Number: number, Hash: hash, ParentHash: parentHash, Status: model.TransactionStatusPending, Transactions: txs}
+}
+
+func removalBatch(address model.Address, hashes ...string) storage.Batch {
+	var batch storage.Batch
+	for _, hash := range hashes {
+		batch.RemoveTransaction(address, hash)
+	}
+
+	return batch
+}
+
+func statusBatch(address model.Address, status model.TransactionStatus, hashes ...string) storage.Batch {
+	var batch storage.Batch
+	for _, hash := range hashes {
+		batch.UpdateTransactionStatus(address, hash, status)
+	}
+
+	return batch
+}
+
+func addressTopic(address model.Address) string {
+	return "0x000000000000000000000000" + string(address)[2:]
+}
+
+func TestParser_StartParsing_Batched(t *testing.T) {
+	mockClient := mocks.NewEthereumClient(t)
+	parser := ethereum.New(100, mockClient, nil, ethereum.WithBatchSize(4))
+
+	mockClient.On("GetLatestBlockNumber", mock.Anything).Return(uint64(110), nil)
+	mockClient.On("GetBlockNumberByTag", mock.Anything, model.BlockTagFinalized).Return(uint64(50), nil)
+
+	blocks := func(from, to uint64) []model.Block {
+		var blocks []model.Block
+		for number := from; number <= to; number++ {
+			blocks = append(blocks, model.Block{Number: number, Hash: fmt.Sprintf("0xBlock%d", number), ParentHash: fmt.Sprintf("0xBlock%d", number-1)})
+		}
+		return blocks
+	}
+
+	mockClient.On("GetBlocksByNumberRange", mock.Anything, uint64(101), uint64(104)).Return(blocks(101, 104), nil).Once()
+	mockClient.On("GetBlocksByNumberRange", mock.Anything, uint64(105), uint64(108)).Return(blocks(105, 108), nil).Once()
+	mockClient.On("GetBlocksByNumberRange", mock.Anything, uint64(109), uint64(110)).Return(blocks(109, 110), nil).Once()
+
+	err := parser.StartParsing(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 110, parser.GetCurrentBlock())
+}
+
+func TestParser_StartParsing_BatchError(t *testing.T) {
+	mockClient := mocks.NewEthereumClient(t)
+	parser := ethereum.New(100, mockClient, nil, ethereum.WithBatchSize(5), ethereum.WithFetchConcurrency(1))
+
+	mockClient.On("GetLatestBlockNumber", mock.Anything).Return(uint64(110), nil)
+	mockClient.On("GetBlockNumberByTag", mock.Anything, model.BlockTagFinalized).Return(uint64(50), nil)
+
+	mockError := errors.New("client error")
+	mockClient.On("GetBlocksByNumberRange", mock.Anything, uint64(101), uint64(105)).Return(nil, mockError).Once()
+	mockClient.On("GetBlocksByNumberRange", mock.Anything, uint64(106), uint64(110)).Return(nil, mockError).Maybe()
+
+	err := parser.StartParsing(context.Background())
+
+	assert.ErrorIs(t, err, mockError)
+	assert.Equal(t, 100, parser.GetCurrentBlock(), "currentBlock must not advance past a failed batch")
+}
+
+func TestParser_Backfill(t *testing.T) {
+	mockClient := mocks.NewEthereumClient(t)
+	mockStorage := storagemocks.NewStorage(t)
+	parser := ethereum.New(99, mockClient, mockStorage, ethereum.WithBackfillConcurrency(2))
+
+	subscribed := model.Address("0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed")
+	liveTx := model.Transaction{Hash: "0xLive", From: "0xAddress2", To: subscribed, BlockNumber: 100}
+	historicTx := model.Transaction{Hash: "0xHistoric", From: subscribed, To: "0xAddress2", BlockNumber: 98}
+	otherTx := model.Transaction{Hash: "0xOther", From: "0xAddress2", To: "0xAddress3", BlockNumber: 99}
+
+	mockClient.On("GetLatestBlockNumber", mock.Anything).Return(uint64(100), nil)
+	mockClient.On("GetBlockNumberByTag", mock.Anything, model.BlockTagFinalized).Return(uint64(50), nil)
+	mockStorage.On("IsSubscribed", mock.Anything, subscribed).Return(true, nil)
+	mockStorage.On("IsSubscribed", mock.Anything, mock.Anything).Return(false, nil)
+
+	// The live tail ingests block 100 first.
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(100)).Return(model.Block{Number: 100, Hash: "0xBlock100", ParentHash: "0xBlock99", Transactions: []model.Transaction{liveTx}}, nil)
+	mockStorage.On("CommitBatch", mock.Anything, transactionBatch(subscribed, withStatus(liveTx, model.TransactionStatusPending))).Return(nil).Once()
+
+	assert.NoError(t, parser.StartParsing(context.Background()))
+
+	mockStorage.On("GetTransactions", mock.Anything, subscribed).Return([]model.Transaction{withStatus(liveTx, model.TransactionStatusPending)}, nil)
+	mockClient.On("GetTransactionsByBlockNumber", mock.Anything, uint64(98)).Return([]model.Transaction{historicTx}, nil)
+	mockClient.On("GetTransactionsByBlockNumber", mock.Anything, uint64(99)).Return([]model.Transaction{otherTx}, nil)
+	mockClient.On("GetTransactionsByBlockNumber", mock.Anything, uint64(100)).Return([]model.Transaction{liveTx}, nil)
+	mockStorage.On("CommitBatch", mock.Anything, transactionBatch(subscribed, withStatus(historicTx, model.TransactionStatusPending))).Return(nil).Once()
+
+	assert.NoError(t, parser.Backfill(context.Background(), string(subscribed), 98))
+
+	assert.Eventually(t, func() bool {
+		progress, ok := parser.GetBackfillProgress(string(subscribed))
+		return ok && progress.Done
+	}, time.Second, 10*time.Millisecond)
+
+	progress, _ := parser.GetBackfillProgress(string(subscribed))
+	assert.Equal(t, ethereum.BackfillProgress{FromBlock: 98, ToBlock: 100, CurrentBlock: 100, Found: 1, Done: true}, progress)
+}
+
+func TestParser_Backfill_SubscriptionExpired(t *testing.T) {
+	mockClient := mocks.NewEthereumClient(t)
+	mockStorage := storagemocks.NewStorage(t)
+	parser := ethereum.New(99, mockClient, mockStorage)
+
+	subscribed := model.Address("0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed")
+	historicTx := model.Transaction{Hash: "0xHistoric", From: subscribed, To: "0xAddress2", BlockNumber: 98}
+
+	mockClient.On("GetLatestBlockNumber", mock.Anything).Return(uint64(100), nil)
+	mockClient.On("GetBlockNumberByTag", mock.Anything, model.BlockTagFinalized).Return(uint64(50), nil)
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(100)).Return(model.Block{Number: 100, Hash: "0xBlock100", ParentHash: "0xBlock99"}, nil)
+
+	assert.NoError(t, parser.StartParsing(context.Background()))
+
+	// The subscription expired after the backfill was requested.
+	mockStorage.On("IsSubscribed", mock.Anything, subscribed).Return(false, nil)
+	mockStorage.On("GetTransactions", mock.Anything, subscribed).Return([]model.Transaction{}, nil)
+	mockClient.On("GetTransactionsByBlockNumber", mock.Anything, uint64(98)).Return([]model.Transaction{historicTx}, nil)
+	mockClient.On("GetTransactionsByBlockNumber", mock.Anything, mock.Anything).Return([]model.Transaction{}, nil).Maybe()
+
+	assert.NoError(t, parser.Backfill(context.Background(), string(subscribed), 98))
+
+	assert.Eventually(t, func() bool {
+		progress, ok := parser.GetBackfillProgress(string(subscribed))
+		return ok && progress.Done
+	}, time.Second, 10*time.Millisecond)
+
+	progress, _ := parser.GetBackfillProgress(string(subscribed))
+	assert.ErrorIs(t, progress.Err, storage.ErrNotSubscribed)
+	assert.Equal(t, 0, progress.Found)
+	mockStorage.AssertNotCalled(t, "CommitBatch", mock.Anything, mock.Anything)
+}
+
+func TestParser_Backfill_AlreadyRunning(t *testing.T) {
+	mockClient := mocks.NewEthereumClient(t)
+	parser := ethereum.New(0, mockClient, nil)
+
+	subscribed := model.Address("0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed")
+
+	assert.ErrorIs(t, parser.Backfill(context.Background(), string(subscribed), 1), ethereum.ErrParserNotStarted)
+
+	mockClient.On("GetLatestBlockNumber", mock.Anything).Return(uint64(100), nil).Once()
+	assert.NoError(t, parser.StartParsing(context.Background()))
+
+	release := make(chan time.Time)
+	mockError := errors.New("client error")
+	mockClient.On("GetLatestBlockNumber", mock.Anything).Return(uint64(0), mockError).WaitUntil(release).Once()
+
+	assert.NoError(t, parser.Backfill(context.Background(), string(subscribed), 0))
+	assert.ErrorIs(t, parser.Backfill(context.Background(), string(subscribed), 0), ethereum.ErrBackfillRunning)
+
+	close(release)
+
+	assert.Eventually(t, func() bool {
+		progress, _ := parser.GetBackfillProgress(string(subscribed))
+		return progress.Done
+	}, time.Second, 10*time.Millisecond)
+
+	progress, _ := parser.GetBackfillProgress(string(subscribed))
+	assert.ErrorIs(t, progress.Err, mockError)
+	assert.Equal(t, uint64(101), progress.FromBlock, "backfill without a start block should scan from the window start")
+}
+
+func TestParser_Run_RetriesAfterErrorAndStops(t *testing.T) {
+	mockClient := mocks.NewEthereumClient(t)
+	parser := ethereum.New(0, mockClient, nil,
+		ethereum.WithPollInterval(time.Hour),
+		ethereum.WithErrorBackoff(time.Millisecond, time.Millisecond),
+	)
+
+	mockError := errors.New("client error")
+	mockClient.On("GetLatestBlockNumber", mock.Anything).Return(uint64(0), mockError).Once()
+	mockClient.On("GetLatestBlockNumber", mock.Anything).Return(uint64(100), nil).Once()
+
+	done := make(chan error)
+	go func() {
+		done <- parser.Run(context.Background())
+	}()
+
+	assert.Eventually(t, func() bool {
+		return !parser.Status().LastSuccess.IsZero()
+	}, time.Second, time.Millisecond)
+
+	status := parser.Status()
+	assert.True(t, status.Running)
+	assert.False(t, status.Lagging)
+	assert.NoError(t, status.LastError)
+	assert.Equal(t, uint64(100), status.CurrentBlock)
+
+	assert.ErrorIs(t, parser.Run(context.Background()), ethereum.ErrAlreadyRunning)
+
+	parser.Stop()
+
+	assert.NoError(t, <-done)
+	assert.False(t, parser.Status().Running)
+}
+
+func TestParser_Run_ParsesOnNewHead(t *testing.T) {
+	mockClient := mocks.NewEthereumClient(t)
+	heads := make(chan uint64)
+	parser := ethereum.New(0, mockClient, nil, ethereum.WithPollInterval(time.Hour), ethereum.WithHeads(heads))
+
+	mockClient.On("GetLatestBlockNumber", mock.Anything).Return(uint64(100), nil).Once()
+	mockClient.On("GetLatestBlockNumber", mock.Anything).Return(uint64(101), nil).Once()
+	mockClient.On("GetBlockNumberByTag", mock.Anything, model.BlockTagFinalized).Return(uint64(50), nil)
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(101)).Return(model.Block{Number: 101, Hash: "0xBlock101"}, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error)
+	go func() {
+		done <- parser.Run(ctx)
+	}()
+
+	assert.Eventually(t, func() bool {
+		return parser.GetCurrentBlock() == 100
+	}, time.Second, time.Millisecond)
+
+	heads <- 101
+
+	assert.Eventually(t, func() bool {
+		return parser.GetCurrentBlock() == 101
+	}, time.Second, time.Millisecond)
+
+	cancel()
+
+	assert.ErrorIs(t, <-done, context.Canceled)
+}
+
+func TestParser_Run_SkipsPollsWhileHeadsArrive(t *testing.T) {
+	mockClient := mocks.NewEthereumClient(t)
+	heads := make(chan uint64)
+	parser := ethereum.New(0, mockClient, nil,
+		ethereum.WithPollInterval(50*time.Millisecond),
+		ethereum.WithHeads(heads),
+	)
+
+	var parses atomic.Int32
+	mockClient.On("GetLatestBlockNumber", mock.Anything).Run(func(mock.Arguments) { parses.Add(1) }).Return(uint64(100), nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error)
+	go func() {
+		done <- parser.Run(ctx)
+	}()
+
+	assert.Eventually(t, func() bool {
+		return parser.GetCurrentBlock() == 100
+	}, time.Second, time.Millisecond)
+
+	// Every head is parsed, and no poll happens in between.
+	for i := 0; i < 20; i++ {
+		heads <- 100
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	assert.Eventually(t, func() bool {
+		return parses.Load() == 21
+	}, time.Second, time.Millisecond)
+
+	// Once the heads go quiet, even with the subscription still open, polling
+	// resumes.
+	assert.Eventually(t, func() bool {
+		return parses.Load() > 22
+	}, time.Second, time.Millisecond)
+
+	cancel()
+
+	assert.ErrorIs(t, <-done, context.Canceled)
+}
+
+func TestParser_Run_PollsAfterHeadsClosed(t *testing.T) {
+	mockClient := mocks.NewEthereumClient(t)
+	heads := make(chan uint64)
+	close(heads)
+
+	parser := ethereum.New(0, mockClient, nil,
+		ethereum.WithPollInterval(10*time.Millisecond),
+		ethereum.WithHeads(heads),
+	)
+
+	var parses atomic.Int32
+	mockClient.On("GetLatestBlockNumber", mock.Anything).Run(func(mock.Arguments) { parses.Add(1) }).Return(uint64(100), nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error)
+	go func() {
+		done <- parser.Run(ctx)
+	}()
+
+	assert.Eventually(t, func() bool {
+		return parses.Load() >= 3
+	}, time.Second, time.Millisecond)
+
+	cancel()
+
+	assert.ErrorIs(t, <-done, context.Canceled)
+	assert.Less(t, parses.Load(), int32(20), "a closed head subscription should not make Run spin")
+}
+
+func TestParser_Run_ShutdownFinishesCurrentBlock(t *testing.T) {
+	mockClient := mocks.NewEthereumClient(t)
+	mockStorage := storagemocks.NewStorage(t)
+	parser := ethereum.New(100, mockClient, mockStorage, ethereum.WithFetchConcurrency(1))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mockClient.On("GetLatestBlockNumber", mock.Anything).Return(uint64(105), nil)
+	mockClient.On("GetBlockNumberByTag", mock.Anything, model.BlockTagFinalized).Return(uint64(50), nil)
+	mockClient.On("GetBlockByNumber", mock.Anything, uint64(101)).Return(model.Block{
+		Number:       101,
+		Hash:         "0xBlock101",
+		Transactions: []model.Transaction{{Hash: "0xHash101", From: "0xSubscribedAddress", To: "0xAddress2"}},
+	}, nil)
+	mockClient.On("GetBlockByNumber", mock.Anything, mock.Anything).Return(model.Block{}, nil).Maybe()
+
+	mockStorage.On("IsSubscribed", mock.Anything, model.Address("0xSubscribedAddress")).Return(true, nil)
+	mockStorage.On("IsSubscribed", mock.Anything, mock.Anything).Return(false, nil)
+	mockStorage.On("CommitBatch", mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			cancel()
+			assert.NoError(t, args.Get(0).(context.Context).Err(), "a block being committed should not see the shutdown")
+		}).
+		Return(nil).Once()
+
+	err := parser.Run(ctx)
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 101, parser.GetCurrentBlock())
+	assert.False(t, parser.Status().Running)
+}
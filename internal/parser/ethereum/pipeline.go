@@ -0,0 +1,172 @@
+package ethereum
+
+import (
+	"context"
+	"fmt"
+	"trustwallet/internal/model"
+)
+
+const (
+	// DefaultFetchConcurrency is how many blocks are fetched in parallel while catching up.
+	DefaultFetchConcurrency = 4
+	// DefaultFetchWindow bounds how many blocks may be fetched ahead of the last committed one.
+	DefaultFetchWindow = 32
+)
+
+type fetched[T any] struct {
+	number uint64
+	value  T
+	err    error
+}
+
+// fetchOrdered fetches every block in [fromBlock, toBlock] with up to workers
+// concurrent calls to fetch, keeping at most window blocks in flight, and
+// delivers the results strictly in block order. Cancelling ctx stops the
+// pipeline and aborts in-flight fetches; the caller must cancel it once it
+// stops reading.
+func fetchOrdered[T any](ctx context.Context, fromBlock, toBlock uint64, workers, window int, fetch func(context.Context, uint64) (T, error)) <-chan fetched[T] {
+	type job struct {
+		number uint64
+		result chan fetched[T]
+	}
+
+	jobs := make(chan job)
+	inFlight := make(chan chan fetched[T], max(window, 1))
+	results := make(chan fetched[T])
+
+	for i := 0; i < max(workers, 1); i++ {
+		go func() {
+			for j := range jobs {
+				value, err := fetch(ctx, j.number)
+				j.result <- fetched[T]{number: j.number, value: value, err: err}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(jobs)
+		defer close(inFlight)
+
+		for number := fromBlock; number <= toBlock; number++ {
+			result := make(chan fetched[T], 1)
+
+			select {
+			case inFlight <- result:
+			case <-ctx.Done():
+				return
+			}
+
+			select {
+			case jobs <- job{number: number, result: result}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	go func() {
+		defer close(results)
+
+		for result := range inFlight {
+			var r fetched[T]
+			select {
+			case r = <-result:
+			case <-ctx.Done():
+				return
+			}
+
+			select {
+			case results <- r:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return results
+}
+
+// fetchBlocks streams the blocks in [fromBlock, toBlock] in order, fetched one
+// by one or in batches depending on the configured batch size. Logs, traces and
+// receipts, when enabled, are fetched alongside the blocks.
+func (p *Parser) fetchBlocks(ctx context.Context, fromBlock, toBlock uint64) <-chan fetched[model.Block] {
+	if p.batchSize <= 1 {
+		fetchBlock := func(ctx context.Context, number uint64) (model.Block, error) {
+			block, err := p.client.GetBlockByNumber(ctx, number)
+			if err != nil {
+				return model.Block{}, err
+			}
+
+			if err := p.attachLogs(ctx, &block); err != nil {
+				return model.Block{}, err
+			}
+
+			if err := p.attachInternalTransfers(ctx, &block); err != nil {
+				return model.Block{}, err
+			}
+
+			return block, p.attachReceipts(ctx, number, block.Transactions, p.involvesSubscribed(ctx))
+		}
+
+		return fetchOrdered(ctx, fromBlock, toBlock, p.fetchConcurrency, p.fetchWindow, fetchBlock)
+	}
+
+	batchSize := uint64(p.batchSize)
+	fetchBatch := func(ctx context.Context, batch uint64) ([]model.Block, error) {
+		batchStart := fromBlock + batch*batchSize
+		blocks, err := p.client.GetBlocksByNumberRange(ctx, batchStart, min(batchStart+batchSize-1, toBlock))
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range blocks {
+			if err := p.attachLogs(ctx, &blocks[i]); err != nil {
+				return nil, err
+			}
+
+			if err := p.attachInternalTransfers(ctx, &blocks[i]); err != nil {
+				return nil, err
+			}
+
+			if err := p.attachReceipts(ctx, blocks[i].Number, blocks[i].Transactions, p.involvesSubscribed(ctx)); err != nil {
+				return nil, err
+			}
+		}
+
+		return blocks, nil
+	}
+
+	batches := fetchOrdered(ctx, 0, (toBlock-fromBlock)/batchSize, p.fetchConcurrency, p.fetchWindow, fetchBatch)
+	blocks := make(chan fetched[model.Block])
+
+	go func() {
+		defer close(blocks)
+
+		for batch := range batches {
+			batchStart := fromBlock + batch.number*batchSize
+			batchEnd := min(batchStart+batchSize-1, toBlock)
+
+			if batch.err == nil && uint64(len(batch.value)) != batchEnd-batchStart+1 {
+				batch.err = fmt.Errorf("batch from block %d returned %d blocks, want %d", batchStart, len(batch.value), batchEnd-batchStart+1)
+			}
+
+			results := []fetched[model.Block]{{number: batchStart, err: batch.err}}
+			if batch.err == nil {
+				results = results[:0]
+				for i, block := range batch.value {
+					results = append(results, fetched[model.Block]{number: batchStart + uint64(i), value: block})
+				}
+			}
+
+			for _, result := range results {
+				select {
+				case blocks <- result:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return blocks
+}
@@ -0,0 +1,71 @@
+package ethereum
+
+import (
+	"context"
+	"fmt"
+	"trustwallet/internal/model"
+)
+
+// attachReceipts sets the receipt of every transaction in txs, which belong to
+// blockNumber, when receipts are enabled. Receipts are only fetched for blocks
+// with at least one transaction that match accepts.
+func (p *Parser) attachReceipts(ctx context.Context, blockNumber uint64, txs []model.Transaction, match func(model.Transaction) (bool, error)) error {
+	if !p.receipts {
+		return nil
+	}
+
+	wanted := false
+	for _, tx := range txs {
+		matched, err := match(tx)
+		if err != nil {
+			return err
+		}
+		if matched {
+			wanted = true
+			break
+		}
+	}
+
+	if !wanted {
+		return nil
+	}
+
+	receipts, err := p.client.GetBlockReceipts(ctx, blockNumber)
+	if err != nil {
+		return fmt.Errorf("receipts of block %d: %w", blockNumber, err)
+	}
+
+	byHash := make(map[string]model.Receipt, len(receipts))
+	for _, receipt := range receipts {
+		byHash[receipt.TransactionHash] = receipt
+	}
+
+	for i := range txs {
+		if receipt, ok := byHash[txs[i].Hash]; ok {
+			txs[i].Receipt = &receipt
+			if receipt.ContractAddress != "" {
+				txs[i].ContractAddress = receipt.ContractAddress
+			}
+		}
+	}
+
+	return nil
+}
+
+// involvesSubscribed reports whether any party of a transaction is a
+// subscribed address.
+func (p *Parser) involvesSubscribed(ctx context.Context) func(model.Transaction) (bool, error) {
+	return func(tx model.Transaction) (bool, error) {
+		for _, party := range tx.Parties() {
+			subscribed, err := p.storage.IsSubscribed(ctx, party)
+			if err != nil {
+				return false, err
+			}
+			if subscribed {
+				return true, nil
+			}
+		}
+
+		return false, nil
+	}
+}
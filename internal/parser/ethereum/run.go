@@ -0,0 +1,185 @@
+package ethereum
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+	"trustwallet/internal/model"
+)
+
+const (
+	// DefaultPollInterval is how often Run checks the chain for new blocks.
+	DefaultPollInterval = time.Second
+
+	// DefaultErrorBackoff is how long Run waits before retrying a failed parse.
+	// The wait doubles on every consecutive failure, up to DefaultMaxErrorBackoff.
+	DefaultErrorBackoff    = time.Second
+	DefaultMaxErrorBackoff = time.Minute
+
+	// DefaultLagThreshold is how many blocks the parser may trail the chain
+	// before Status reports it as lagging.
+	DefaultLagThreshold = 5
+)
+
+var ErrAlreadyRunning = errors.New("parser is already running")
+
+var errStopped = errors.New("parser stopped")
+
+// Status is a snapshot of the parser's health.
+type Status struct {
+	Running      bool
+	Lagging      bool
+	CurrentBlock uint64
+	HeadBlock    uint64
+	LastError    error
+	LastSuccess  time.Time
+}
+
+// Run keeps the parser in sync with the chain until ctx is cancelled or Stop
+// is called. It parses whenever a head arrives on the channel passed to
+// WithHeads, and every poll interval unless a head arrived within it, so a
+// subscription that goes quiet falls back to polling. A failed parse is
+// logged and retried with exponential backoff. On shutdown the block being
+// committed is finished before Run returns. Run returns nil after Stop and
+// ctx's error otherwise.
+func (p *Parser) Run(ctx context.Context) error {
+	p.runMu.Lock()
+	if p.running {
+		p.runMu.Unlock()
+		return ErrAlreadyRunning
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	p.running = true
+	p.stop = cancel
+	p.stopped = make(chan struct{})
+	p.runMu.Unlock()
+
+	defer func() {
+		p.runMu.Lock()
+		p.running = false
+		close(p.stopped)
+		p.runMu.Unlock()
+
+		cancel(nil)
+	}()
+
+	var (
+		delay    time.Duration
+		backoff  time.Duration
+		lastHead time.Time
+	)
+
+	subscription := p.heads
+
+	for {
+		// Heads are ignored while backing off so a failing node is not hammered.
+		heads := subscription
+		if backoff > 0 {
+			heads = nil
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			if errors.Is(context.Cause(ctx), errStopped) {
+				return nil
+			}
+			return ctx.Err()
+		case head, ok := <-heads:
+			timer.Stop()
+			if !ok {
+				log.Println("Head subscription closed, polling only")
+				subscription = nil
+				continue
+			}
+
+			lastHead = time.Now()
+
+			// Heads say nothing about safe or finalized blocks.
+			if p.followTag == model.BlockTagLatest {
+				p.observeHead(head)
+			}
+		case <-timer.C:
+			// Polls are skipped while heads arrive; retries after an error are not.
+			if backoff == 0 && delay > 0 && time.Since(lastHead) < p.pollInterval {
+				continue
+			}
+		}
+
+		err := p.StartParsing(ctx)
+		if ctx.Err() != nil {
+			continue
+		}
+
+		p.recordRun(err)
+
+		if err != nil {
+			backoff = min(max(2*backoff, p.errorBackoff), p.maxErrorBackoff)
+			delay = backoff
+			log.Println("error parsing, retrying in", backoff, err)
+			continue
+		}
+
+		backoff = 0
+		delay = p.pollInterval
+	}
+}
+
+// Stop makes Run return once the block being committed is finished, and waits
+// for it. It does nothing if the parser is not running.
+func (p *Parser) Stop() {
+	p.runMu.Lock()
+	if !p.running {
+		p.runMu.Unlock()
+		return
+	}
+
+	p.stop(errStopped)
+	stopped := p.stopped
+	p.runMu.Unlock()
+
+	<-stopped
+}
+
+// Status reports whether the parser is running, how far it trails the chain
+// and the outcome of its most recent parses.
+func (p *Parser) Status() Status {
+	p.runMu.Lock()
+	running := p.running
+	p.runMu.Unlock()
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	return Status{
+		Running:      running,
+		Lagging:      p.headBlock > p.currentBlock+p.lagThreshold,
+		CurrentBlock: p.currentBlock,
+		HeadBlock:    p.headBlock,
+		LastError:    p.lastErr,
+		LastSuccess:  p.lastSuccess,
+	}
+}
+
+func (p *Parser) recordRun(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.lastErr = err
+	if err == nil {
+		p.lastSuccess = time.Now()
+	}
+}
+
+// observeHead remembers the highest block the parser knows it has to reach.
+func (p *Parser) observeHead(head uint64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if head > p.headBlock {
+		p.headBlock = head
+	}
+}
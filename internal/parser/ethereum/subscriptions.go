@@ -0,0 +1,71 @@
+package ethereum
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"trustwallet/internal/model"
+)
+
+type SubscriptionOption func(*model.Subscription)
+
+// WithLabel attaches a free-form label to a subscription.
+func WithLabel(label string) SubscriptionOption {
+	return func(s *model.Subscription) {
+		s.Label = label
+	}
+}
+
+// WithExpiry makes a subscription stop matching new activity at the given
+// time. Activity recorded before then is kept until the address is
+// unsubscribed with purge.
+func WithExpiry(at time.Time) SubscriptionOption {
+	return func(s *model.Subscription) {
+		s.ExpiresAt = &at
+	}
+}
+
+// Unsubscribe stops recording the activity of address. With purge set, the
+// transactions and transfers already stored for it are deleted too. It
+// returns storage.ErrNotSubscribed if address is not subscribed.
+func (p *Parser) Unsubscribe(address string, purge bool) error {
+	return p.UnsubscribeContext(context.Background(), address, purge)
+}
+
+func (p *Parser) UnsubscribeContext(ctx context.Context, address string, purge bool) error {
+	parsed, err := model.ParseAddress(address)
+	if err != nil {
+		return err
+	}
+
+	if err := p.storage.RemoveSubscription(ctx, parsed, purge); err != nil {
+		return fmt.Errorf("unsubscribe from %s: %w", parsed, err)
+	}
+
+	return nil
+}
+
+// ListSubscriptions returns a page of up to limit subscriptions ordered by
+// address, starting after the address given as after. Pass "" to start from
+// the beginning and the last address of a page to get the next one. A limit
+// of zero or less returns every subscription.
+func (p *Parser) ListSubscriptions(after string, limit int) ([]model.Subscription, error) {
+	return p.ListSubscriptionsContext(context.Background(), after, limit)
+}
+
+func (p *Parser) ListSubscriptionsContext(ctx context.Context, after string, limit int) ([]model.Subscription, error) {
+	var cursor model.Address
+	if after != "" {
+		var err error
+		if cursor, err = model.ParseAddress(after); err != nil {
+			return nil, err
+		}
+	}
+
+	subscriptions, err := p.storage.ListSubscriptions(ctx, cursor, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list subscriptions: %w", err)
+	}
+
+	return subscriptions, nil
+}
@@ -0,0 +1,302 @@
+package ethereum
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"trustwallet/internal/model"
+	"trustwallet/internal/storage"
+)
+
+const (
+	// TransferTopic is the signature of Transfer(address,address,uint256), the
+	// event ERC-20 and ERC-721 tokens emit for every transfer.
+	TransferTopic = "0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef"
+	// TransferSingleTopic is the signature of the ERC-1155 event
+	// TransferSingle(address,address,address,uint256,uint256).
+	TransferSingleTopic = "0xc3d58168c5ae7397731d063d5bbf3d657854427343f4c083240f7aacaa2d0f62"
+	// TransferBatchTopic is the signature of the ERC-1155 event
+	// TransferBatch(address,address,address,uint256[],uint256[]).
+	TransferBatchTopic = "0x4a39dc06d4c0dbc64b70af90fd698a233a518aa5d07e595d983b8c0526c8f7fb"
+)
+
+// logTopics returns the event signatures of the transfers the parser tracks.
+func (p *Parser) logTopics() []string {
+	var topics []string
+	if p.tokenTransfers || p.nftTransfers {
+		topics = append(topics, TransferTopic)
+	}
+	if p.nftTransfers {
+		topics = append(topics, TransferSingleTopic, TransferBatchTopic)
+	}
+
+	return topics
+}
+
+// attachLogs fetches the token transfer logs of block when token or NFT
+// transfers are tracked.
+func (p *Parser) attachLogs(ctx context.Context, block *model.Block) error {
+	topics := p.logTopics()
+	if len(topics) == 0 {
+		return nil
+	}
+
+	if matches, err := p.bloomMatches(ctx, *block); err != nil || !matches {
+		return err
+	}
+
+	logs, err := p.client.GetLogs(ctx, block.Number, topics)
+	if err != nil {
+		return fmt.Errorf("logs of block %d: %w", block.Number, err)
+	}
+
+	block.Logs = logs
+	return nil
+}
+
+// parseTokenTransfers adds the token transfers in block's logs to batch for
+// every subscribed party and returns what it added.
+func (p *Parser) parseTokenTransfers(ctx context.Context, block model.Block, status model.TransactionStatus, batch *storage.Batch) ([]storedTransfer, error) {
+	var stored []storedTransfer
+	for _, l := range block.Logs {
+		transfer, ok := decodeTokenTransfer(l)
+		if !ok || !p.tracks(transfer.Standard) {
+			continue
+		}
+
+		transfer.BlockNumber = block.Number
+		transfer.Status = status
+
+		for _, party := range transfer.Parties() {
+			subscribed, err := p.storage.IsSubscribed(ctx, party)
+			if err != nil {
+				return nil, err
+			}
+			if !subscribed {
+				continue
+			}
+
+			batch.AddTokenTransfer(party, transfer)
+			stored = append(stored, storedTransfer{address: party, hash: transfer.TransactionHash, logIndex: transfer.LogIndex})
+		}
+	}
+
+	return stored, nil
+}
+
+// tracks reports whether the parser stores transfers of the given standard.
+func (p *Parser) tracks(standard model.TokenStandard) bool {
+	if standard.IsNFT() {
+		return p.nftTransfers
+	}
+
+	return p.tokenTransfers
+}
+
+// decodeTokenTransfer decodes an ERC-20 or ERC-721 Transfer event or an
+// ERC-1155 TransferSingle or TransferBatch event. ERC-20 and ERC-721 share the
+// Transfer signature and are told apart by ERC-721 indexing the token id as a
+// fourth topic. It reports false for any other log.
+func decodeTokenTransfer(l model.Log) (model.TokenTransfer, bool) {
+	if len(l.Topics) == 0 {
+		return model.TokenTransfer{}, false
+	}
+
+	var (
+		transfer model.TokenTransfer
+		ok       bool
+	)
+	switch strings.ToLower(l.Topics[0]) {
+	case TransferTopic:
+		transfer, ok = decodeTransfer(l)
+	case TransferSingleTopic:
+		transfer, ok = decodeTransferSingle(l)
+	case TransferBatchTopic:
+		transfer, ok = decodeTransferBatch(l)
+	}
+	if !ok {
+		return model.TokenTransfer{}, false
+	}
+
+	transfer.Token = l.Address
+	transfer.TransactionHash = l.TransactionHash
+	transfer.LogIndex = l.LogIndex
+	return transfer, true
+}
+
+// decodeTransfer decodes Transfer(from, to, amount) for ERC-20 and
+// Transfer(from, to, tokenId) with all arguments indexed for ERC-721.
+func decodeTransfer(l model.Log) (model.TokenTransfer, bool) {
+	if len(l.Topics) != 3 && len(l.Topics) != 4 {
+		return model.TokenTransfer{}, false
+	}
+
+	from, ok := topicAddress(l.Topics[1])
+	if !ok {
+		return model.TokenTransfer{}, false
+	}
+
+	to, ok := topicAddress(l.Topics[2])
+	if !ok {
+		return model.TokenTransfer{}, false
+	}
+
+	if len(l.Topics) == 4 {
+		tokenID, ok := topicWord(l.Topics[3])
+		if !ok {
+			return model.TokenTransfer{}, false
+		}
+
+		return model.TokenTransfer{
+			Standard: model.TokenStandardERC721,
+			From:     from,
+			To:       to,
+			TokenID:  tokenID,
+			Amount:   big.NewInt(1),
+		}, true
+	}
+
+	amount, ok := word(l.Data, 0)
+	if !ok {
+		return model.TokenTransfer{}, false
+	}
+
+	return model.TokenTransfer{
+		Standard: model.TokenStandardERC20,
+		From:     from,
+		To:       to,
+		Amount:   amount,
+	}, true
+}
+
+// decodeTransferSingle decodes TransferSingle(operator, from, to, id, value),
+// whose first three arguments are indexed.
+func decodeTransferSingle(l model.Log) (model.TokenTransfer, bool) {
+	transfer, ok := decodeERC1155Parties(l)
+	if !ok {
+		return model.TokenTransfer{}, false
+	}
+
+	if transfer.TokenID, ok = word(l.Data, 0); !ok {
+		return model.TokenTransfer{}, false
+	}
+
+	if transfer.Amount, ok = word(l.Data, 1); !ok {
+		return model.TokenTransfer{}, false
+	}
+
+	return transfer, true
+}
+
+// decodeTransferBatch decodes TransferBatch(operator, from, to, ids, values),
+// whose first three arguments are indexed and whose id and value arrays are
+// ABI encoded in the data.
+func decodeTransferBatch(l model.Log) (model.TokenTransfer, bool) {
+	transfer, ok := decodeERC1155Parties(l)
+	if !ok {
+		return model.TokenTransfer{}, false
+	}
+
+	ids, ok := wordArray(l.Data, 0)
+	if !ok {
+		return model.TokenTransfer{}, false
+	}
+
+	amounts, ok := wordArray(l.Data, 1)
+	if !ok || len(ids) != len(amounts) {
+		return model.TokenTransfer{}, false
+	}
+
+	transfer.Batch = make([]model.TokenAmount, len(ids))
+	for i := range ids {
+		transfer.Batch[i] = model.TokenAmount{TokenID: ids[i], Amount: amounts[i]}
+	}
+
+	return transfer, true
+}
+
+// decodeERC1155Parties decodes the indexed operator, from and to of an ERC-1155 event.
+func decodeERC1155Parties(l model.Log) (model.TokenTransfer, bool) {
+	if len(l.Topics) != 4 {
+		return model.TokenTransfer{}, false
+	}
+
+	operator, ok := topicAddress(l.Topics[1])
+	if !ok {
+		return model.TokenTransfer{}, false
+	}
+
+	from, ok := topicAddress(l.Topics[2])
+	if !ok {
+		return model.TokenTransfer{}, false
+	}
+
+	to, ok := topicAddress(l.Topics[3])
+	if !ok {
+		return model.TokenTransfer{}, false
+	}
+
+	return model.TokenTransfer{
+		Standard: model.TokenStandardERC1155,
+		Operator: operator,
+		From:     from,
+		To:       to,
+	}, true
+}
+
+// topicAddress decodes an address indexed as a 32 byte topic.
+func topicAddress(topic string) (model.Address, bool) {
+	if len(topic) != 66 || !strings.HasPrefix(topic, "0x") || strings.Trim(topic[2:26], "0") != "" {
+		return "", false
+	}
+
+	address, err := model.ParseAddress("0x" + strings.ToLower(topic[26:]))
+	return address, err == nil
+}
+
+// topicWord decodes a 32 byte topic as an unsigned integer.
+func topicWord(topic string) (*big.Int, bool) {
+	if len(topic) != 66 || !strings.HasPrefix(topic, "0x") {
+		return nil, false
+	}
+
+	return word(topic, 0)
+}
+
+// word decodes the i-th 32 byte word of hex encoded ABI data as an unsigned integer.
+func word(data string, i int) (*big.Int, bool) {
+	data = strings.TrimPrefix(data, "0x")
+	if i < 0 || i >= len(data)/64 {
+		return nil, false
+	}
+
+	return new(big.Int).SetString(data[64*i:64*(i+1)], 16)
+}
+
+// wordArray decodes the dynamic uint256 array whose offset is the i-th word of
+// hex encoded ABI data. The offset and length come from the log, which any
+// contract can emit, so both are bounded by the size of the data before use.
+func wordArray(data string, i int) ([]*big.Int, bool) {
+	data = strings.TrimPrefix(data, "0x")
+
+	offset, ok := word(data, i)
+	if !ok || offset.Cmp(big.NewInt(int64(len(data)/2))) > 0 || offset.Int64()%32 != 0 {
+		return nil, false
+	}
+
+	start := int(offset.Int64() / 32)
+	length, ok := word(data, start)
+	if !ok || length.Cmp(big.NewInt(int64(len(data)/64))) > 0 {
+		return nil, false
+	}
+
+	values := make([]*big.Int, length.Int64())
+	for j := range values {
+		if values[j], ok = word(data, start+1+j); !ok {
+			return nil, false
+		}
+	}
+
+	return values, true
+}
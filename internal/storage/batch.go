@@ -0,0 +1,116 @@
+package storage
+
+import "trustwallet/internal/model"
+
+// Batch collects the writes for one block, or for a rollback or status update
+// of several, so Storage.CommitBatch can apply them all at once or not at all.
+// Removals are applied first, then additions, then status updates.
+type Batch struct {
+	Transactions      []AddressTransaction
+	TokenTransfers    []AddressTokenTransfer
+	InternalTransfers []AddressInternalTransfer
+
+	RemovedTransactions      []TransactionKey
+	RemovedTokenTransfers    []TransferKey
+	RemovedInternalTransfers []TransferKey
+
+	TransactionStatuses      []TransactionStatusUpdate
+	TokenTransferStatuses    []TransferStatusUpdate
+	InternalTransferStatuses []TransferStatusUpdate
+
+	// Checkpoint, if set, is saved together with the writes, for storages that
+	// also keep the parser's checkpoint.
+	Checkpoint *model.Checkpoint
+}
+
+// AddressTransaction is a transaction stored for an address.
+type AddressTransaction struct {
+	Address     model.Address
+	Transaction model.Transaction
+}
+
+// AddressTokenTransfer is a token transfer stored for an address.
+type AddressTokenTransfer struct {
+	Address  model.Address
+	Transfer model.TokenTransfer
+}
+
+// AddressInternalTransfer is an internal transfer stored for an address.
+type AddressInternalTransfer struct {
+	Address  model.Address
+	Transfer model.InternalTransfer
+}
+
+// TransactionKey identifies a transaction stored for an address.
+type TransactionKey struct {
+	Address model.Address
+	Hash    string
+}
+
+// TransferKey identifies a token or internal transfer stored for an address
+// by its transaction and its log or call index.
+type TransferKey struct {
+	Address model.Address
+	Hash    string
+	Index   uint64
+}
+
+// TransactionStatusUpdate sets the status of a stored transaction.
+type TransactionStatusUpdate struct {
+	TransactionKey
+	Status model.TransactionStatus
+}
+
+// TransferStatusUpdate sets the status of a stored token or internal transfer.
+type TransferStatusUpdate struct {
+	TransferKey
+	Status model.TransactionStatus
+}
+
+func (b *Batch) AddTransaction(address model.Address, tx model.Transaction) {
+	b.Transactions = append(b.Transactions, AddressTransaction{Address: address, Transaction: tx})
+}
+
+func (b *Batch) AddTokenTransfer(address model.Address, transfer model.TokenTransfer) {
+	b.TokenTransfers = append(b.TokenTransfers, AddressTokenTransfer{Address: address, Transfer: transfer})
+}
+
+func (b *Batch) AddInternalTransfer(address model.Address, transfer model.InternalTransfer) {
+	b.InternalTransfers = append(b.InternalTransfers, AddressInternalTransfer{Address: address, Transfer: transfer})
+}
+
+func (b *Batch) RemoveTransaction(address model.Address, hash string) {
+	b.RemovedTransactions = append(b.RemovedTransactions, TransactionKey{Address: address, Hash: hash})
+}
+
+func (b *Batch) RemoveTokenTransfer(address model.Address, hash string, logIndex uint64) {
+	b.RemovedTokenTransfers = append(b.RemovedTokenTransfers, TransferKey{Address: address, Hash: hash, Index: logIndex})
+}
+
+func (b *Batch) RemoveInternalTransfer(address model.Address, hash string, index uint64) {
+	b.RemovedInternalTransfers = append(b.RemovedInternalTransfers, TransferKey{Address: address, Hash: hash, Index: index})
+}
+
+func (b *Batch) UpdateTransactionStatus(address model.Address, hash string, status model.TransactionStatus) {
+	b.TransactionStatuses = append(b.TransactionStatuses, TransactionStatusUpdate{TransactionKey: TransactionKey{Address: address, Hash: hash}, Status: status})
+}
+
+func (b *Batch) UpdateTokenTransferStatus(address model.Address, hash string, logIndex uint64, status model.TransactionStatus) {
+	b.TokenTransferStatuses = append(b.TokenTransferStatuses, TransferStatusUpdate{TransferKey: TransferKey{Address: address, Hash: hash, Index: logIndex}, Status: status})
+}
+
+func (b *Batch) UpdateInternalTransferStatus(address model.Address, hash string, index uint64, status model.TransactionStatus) {
+	b.InternalTransferStatuses = append(b.InternalTransferStatuses, TransferStatusUpdate{TransferKey: TransferKey{Address: address, Hash: hash, Index: index}, Status: status})
+}
+
+func (b *Batch) SaveCheckpoint(checkpoint model.Checkpoint) {
+	b.Checkpoint = &checkpoint
+}
+
+// Empty reports whether committing the batch would change nothing.
+func (b *Batch) Empty() bool {
+	return len(b.Transactions) == 0 && len(b.TokenTransfers) == 0 && len(b.InternalTransfers) == 0 &&
+		len(b.RemovedTransactions) == 0 && len(b.RemovedTokenTransfers) == 0 && len(b.RemovedInternalTransfers) == 0 &&
+		len(b.TransactionStatuses) == 0 && len(b.TokenTransferStatuses) == 0 && len(b.InternalTransferStatuses) == 0 &&
+		b.Checkpoint == nil
+}
@@ -0,0 +1,83 @@
+package bolt
+
+import (
+	"encoding/binary"
+	"trustwallet/internal/model"
+)
+
+// The database holds one bucket per kind of record. Keys start with the
+// address or hash they belong to followed by a zero byte, so a prefix scan
+// finds everything stored for it and keys sort by address first:
+//
+//	subscriptions         address 0                    -> Subscription
+//	transactions          address 0 seq                -> Transaction
+//	transactionKeys       address 0 hash               -> seq
+//	transactionOrder      address 0 block txIndex hash -> empty
+//	tokenTransfers        address 0 seq                -> TokenTransfer
+//	tokenTransferKeys     address 0 hash 0 logIndex    -> seq
+//	internalTransfers     address 0 seq                -> InternalTransfer
+//	internalTransferKeys  address 0 hash 0 index       -> seq
+//	holders               hash 0 address               -> empty
+//	meta                  "checkpoint"                 -> Checkpoint
+//
+// seq is the sequence number a record was first stored under, which keeps
+// records in insertion order. transactionOrder sorts each address's
+// transactions the way queries order them, so a query reads only its page.
+// Numbers are big-endian uint64s and values JSON.
+var (
+	subscriptionsBucket        = []byte("subscriptions")
+	transactionsBucket         = []byte("transactions")
+	transactionKeysBucket      = []byte("transactionKeys")
+	transactionOrderBucket     = []byte("transactionOrder")
+	tokenTransfersBucket       = []byte("tokenTransfers")
+	tokenTransferKeysBucket    = []byte("tokenTransferKeys")
+	internalTransfersBucket    = []byte("internalTransfers")
+	internalTransferKeysBucket = []byte("internalTransferKeys")
+	holdersBucket              = []byte("holders")
+	metaBucket                 = []byte("meta")
+
+	checkpointKey = []byte("checkpoint")
+)
+
+var buckets = [][]byte{
+	subscriptionsBucket,
+	transactionsBucket,
+	transactionKeysBucket,
+	transactionOrderBucket,
+	tokenTransfersBucket,
+	tokenTransferKeysBucket,
+	internalTransfersBucket,
+	internalTransferKeysBucket,
+	holdersBucket,
+	metaBucket,
+}
+
+// prefix terminates s with a zero byte. Addresses and hashes never contain
+// one, so no prefix is the start of another.
+func prefix(s string) []byte {
+	return append([]byte(s), 0)
+}
+
+func uint64Key(n uint64) []byte {
+	return binary.BigEndian.AppendUint64(nil, n)
+}
+
+// transferKey identifies a transfer by its transaction and its log or call
+// frame index.
+func transferKey(hash string, index uint64) []byte {
+	return append(prefix(hash), uint64Key(index)...)
+}
+
+func join(parts ...[]byte) []byte {
+	var key []byte
+	for _, part := range parts {
+		key = append(key, part...)
+	}
+
+	return key
+}
+
+// orderKey places a transaction of address in the transactionOrder bucket.
+func orderKey(address model.Address, position model.TransactionPosition) []byte {
+	return join(prefix(string(address)), uint64Key(position.BlockNumber), uint64Key(position.TransactionIndex), []byte(position.Hash))
+}
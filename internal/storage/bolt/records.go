@@ -0,0 +1,134 @@
+package bolt
+
+import (
+	"bytes"
+	"encoding/json"
+	"go.etcd.io/bbolt"
+	"trustwallet/internal/model"
+)
+
+// records stores values per address in insertion order, at most one per key,
+// in a values bucket keyed by sequence number and a keys bucket indexing each
+// key's sequence number.
+type records[V any] struct {
+	values []byte
+	keys   []byte
+}
+
+// put replaces the value stored under key in place, or appends it.
+func (r records[V]) put(tx *bbolt.Tx, address model.Address, key []byte, value V) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	values, keys := tx.Bucket(r.values), tx.Bucket(r.keys)
+	indexKey := join(prefix(string(address)), key)
+
+	seq := bytes.Clone(keys.Get(indexKey))
+	if seq == nil {
+		n, err := values.NextSequence()
+		if err != nil {
+			return err
+		}
+
+		seq = uint64Key(n)
+		if err := keys.Put(indexKey, seq); err != nil {
+			return err
+		}
+	}
+
+	return values.Put(join(prefix(string(address)), seq), data)
+}
+
+func (r records[V]) get(tx *bbolt.Tx, address model.Address, key []byte) (V, bool, error) {
+	var value V
+
+	seq := tx.Bucket(r.keys).Get(join(prefix(string(address)), key))
+	if seq == nil {
+		return value, false, nil
+	}
+
+	data := tx.Bucket(r.values).Get(join(prefix(string(address)), seq))
+	if err := json.Unmarshal(data, &value); err != nil {
+		return value, false, err
+	}
+
+	return value, true, nil
+}
+
+// update applies fn to the value stored under key, if any.
+func (r records[V]) update(tx *bbolt.Tx, address model.Address, key []byte, fn func(*V)) error {
+	value, ok, err := r.get(tx, address, key)
+	if err != nil || !ok {
+		return err
+	}
+
+	fn(&value)
+
+	return r.put(tx, address, key, value)
+}
+
+// remove deletes the value stored under key and reports whether there was one.
+func (r records[V]) remove(tx *bbolt.Tx, address model.Address, key []byte) (bool, error) {
+	keys := tx.Bucket(r.keys)
+	indexKey := join(prefix(string(address)), key)
+
+	seq := bytes.Clone(keys.Get(indexKey))
+	if seq == nil {
+		return false, nil
+	}
+
+	if err := keys.Delete(indexKey); err != nil {
+		return false, err
+	}
+
+	return true, tx.Bucket(r.values).Delete(join(prefix(string(address)), seq))
+}
+
+// list returns the values stored for address in insertion order.
+func (r records[V]) list(tx *bbolt.Tx, address model.Address) ([]V, error) {
+	p := prefix(string(address))
+
+	values := []V{}
+	c := tx.Bucket(r.values).Cursor()
+	for k, data := c.Seek(p); k != nil && bytes.HasPrefix(k, p); k, data = c.Next() {
+		var value V
+		if err := json.Unmarshal(data, &value); err != nil {
+			return nil, err
+		}
+
+		values = append(values, value)
+	}
+
+	return values, nil
+}
+
+// purge deletes everything stored for address.
+func (r records[V]) purge(tx *bbolt.Tx, address model.Address) error {
+	for _, name := range [][]byte{r.values, r.keys} {
+		if err := deletePrefix(tx.Bucket(name), prefix(string(address))); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deletePrefix deletes the keys of bucket starting with p. The keys are
+// collected first, as deleting while iterating can make a cursor skip keys.
+func deletePrefix(bucket *bbolt.Bucket, p []byte) error {
+	var keys [][]byte
+	c := bucket.Cursor()
+	for k, _ := c.Seek(p); k != nil && bytes.HasPrefix(k, p); k, _ = c.Next() {
+		keys = append(keys, bytes.Clone(k))
+	}
+
+	for _, k := range keys {
+		if err := bucket.Delete(k); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,551 @@
+package bolt
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"go.etcd.io/bbolt"
+	"math"
+	"time"
+	"trustwallet/internal/model"
+	"trustwallet/internal/storage"
+)
+
+var (
+	transactions      = records[model.Transaction]{values: transactionsBucket, keys: transactionKeysBucket}
+	tokenTransfers    = records[model.TokenTransfer]{values: tokenTransfersBucket, keys: tokenTransferKeysBucket}
+	internalTransfers = records[model.InternalTransfer]{values: internalTransfersBucket, keys: internalTransferKeysBucket}
+)
+
+// Bolt keeps the subscriptions, their activity and the parser checkpoint in a
+// bbolt database file, so they survive restarts. Every write is its own
+// transaction and CommitBatch applies a whole batch in one. Like InMemory,
+// storing an entry again replaces it instead of adding a duplicate.
+type Bolt struct {
+	db *bbolt.DB
+}
+
+// Open opens the database at path, creating it if it does not exist. Only one
+// process can have it open at a time.
+func Open(path string) (*Bolt, error) {
+	db, err := bbolt.Open(path, 0o600, &bbolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(func(tx *bbolt.Tx) error {
+		for _, name := range buckets {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &Bolt{db: db}, nil
+}
+
+func (b *Bolt) Close() error {
+	return b.db.Close()
+}
+
+func (b *Bolt) AddAddress(ctx context.Context, address model.Address) error {
+	return b.AddSubscription(ctx, model.Subscription{Address: address, CreatedAt: time.Now().UTC()})
+}
+
+func (b *Bolt) AddSubscription(_ context.Context, subscription model.Subscription) error {
+	return b.db.Update(func(tx *bbolt.Tx) error {
+		subscriptions := tx.Bucket(subscriptionsBucket)
+		key := prefix(string(subscription.Address))
+
+		if data := subscriptions.Get(key); data != nil {
+			var existing model.Subscription
+			if err := json.Unmarshal(data, &existing); err != nil {
+				return err
+			}
+
+			subscription.CreatedAt = existing.CreatedAt
+		}
+
+		data, err := json.Marshal(subscription)
+		if err != nil {
+			return err
+		}
+
+		return subscriptions.Put(key, data)
+	})
+}
+
+func (b *Bolt) RemoveSubscription(_ context.Context, address model.Address, purge bool) error {
+	return b.db.Update(func(tx *bbolt.Tx) error {
+		subscriptions := tx.Bucket(subscriptionsBucket)
+		key := prefix(string(address))
+
+		if subscriptions.Get(key) == nil {
+			return storage.ErrNotSubscribed
+		}
+
+		if err := subscriptions.Delete(key); err != nil {
+			return err
+		}
+
+		if !purge {
+			return nil
+		}
+
+		txs, err := transactions.list(tx, address)
+		if err != nil {
+			return err
+		}
+
+		for _, stored := range txs {
+			if err := tx.Bucket(holdersBucket).Delete(join(prefix(stored.Hash), []byte(address))); err != nil {
+				return err
+			}
+		}
+
+		if err := transactions.purge(tx, address); err != nil {
+			return err
+		}
+
+		if err := deletePrefix(tx.Bucket(transactionOrderBucket), prefix(string(address))); err != nil {
+			return err
+		}
+
+		if err := tokenTransfers.purge(tx, address); err != nil {
+			return err
+		}
+
+		return internalTransfers.purge(tx, address)
+	})
+}
+
+func (b *Bolt) IsSubscribed(_ context.Context, address model.Address) (bool, error) {
+	var subscribed bool
+	err := b.db.View(func(tx *bbolt.Tx) error {
+		data := tx.Bucket(subscriptionsBucket).Get(prefix(string(address)))
+		if data == nil {
+			return nil
+		}
+
+		var subscription model.Subscription
+		if err := json.Unmarshal(data, &subscription); err != nil {
+			return err
+		}
+
+		subscribed = !subscription.Expired(time.Now())
+		return nil
+	})
+
+	return subscribed, err
+}
+
+func (b *Bolt) ListAddresses(ctx context.Context) ([]model.Address, error) {
+	subscriptions, err := b.ListSubscriptions(ctx, "", 0)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	addresses := make([]model.Address, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		if !subscription.Expired(now) {
+			addresses = append(addresses, subscription.Address)
+		}
+	}
+
+	return addresses, nil
+}
+
+// ListSubscriptions walks the subscriptions bucket, whose keys sort by address.
+func (b *Bolt) ListSubscriptions(_ context.Context, after model.Address, limit int) ([]model.Subscription, error) {
+	subscriptions := []model.Subscription{}
+	err := b.db.View(func(tx *bbolt.Tx) error {
+		start := prefix(string(after))
+
+		c := tx.Bucket(subscriptionsBucket).Cursor()
+		for k, data := c.Seek(start); k != nil; k, data = c.Next() {
+			if bytes.Equal(k, start) {
+				continue
+			}
+
+			if limit > 0 && len(subscriptions) == limit {
+				break
+			}
+
+			var subscription model.Subscription
+			if err := json.Unmarshal(data, &subscription); err != nil {
+				return err
+			}
+
+			subscriptions = append(subscriptions, subscription)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return subscriptions, nil
+}
+
+func (b *Bolt) AddTransaction(_ context.Context, address model.Address, tx model.Transaction) error {
+	return b.db.Update(func(btx *bbolt.Tx) error {
+		return putTransaction(btx, address, tx)
+	})
+}
+
+// putTransaction stores tx for address, indexes it by query order and records
+// address as a holder of its hash. A replaced transaction may have moved to
+// another block, so its old index entry is dropped.
+func putTransaction(btx *bbolt.Tx, address model.Address, tx model.Transaction) error {
+	order := btx.Bucket(transactionOrderBucket)
+
+	stored, ok, err := transactions.get(btx, address, []byte(tx.Hash))
+	if err != nil {
+		return err
+	}
+	if ok {
+		if err := order.Delete(orderKey(address, model.PositionOf(stored))); err != nil {
+			return err
+		}
+	}
+
+	if err := transactions.put(btx, address, []byte(tx.Hash), tx); err != nil {
+		return err
+	}
+
+	if err := order.Put(orderKey(address, model.PositionOf(tx)), []byte{}); err != nil {
+		return err
+	}
+
+	return btx.Bucket(holdersBucket).Put(join(prefix(tx.Hash), []byte(address)), []byte{})
+}
+
+func (b *Bolt) GetTransactions(_ context.Context, address model.Address) ([]model.Transaction, error) {
+	var txs []model.Transaction
+	err := b.db.View(func(btx *bbolt.Tx) error {
+		var err error
+		txs, err = transactions.list(btx, address)
+		return err
+	})
+
+	return txs, err
+}
+
+// GetTransactionByHash reads the transaction from the first address holding it.
+func (b *Bolt) GetTransactionByHash(_ context.Context, hash string) (model.Transaction, error) {
+	var tx model.Transaction
+	err := b.db.View(func(btx *bbolt.Tx) error {
+		p := prefix(hash)
+
+		k, _ := btx.Bucket(holdersBucket).Cursor().Seek(p)
+		if k == nil || !bytes.HasPrefix(k, p) {
+			return storage.ErrNotFound
+		}
+
+		stored, ok, err := transactions.get(btx, model.Address(k[len(p):]), []byte(hash))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return storage.ErrNotFound
+		}
+
+		tx = stored
+		return nil
+	})
+
+	return tx, err
+}
+
+// QueryTransactions walks the transactionOrder bucket in the query's order,
+// starting at its block range or cursor, and reads only the transactions it
+// passes until the page is full.
+func (b *Bolt) QueryTransactions(_ context.Context, address model.Address, query model.TransactionQuery) (model.TransactionPage, error) {
+	after, hasCursor, err := query.After()
+	if err != nil {
+		return model.TransactionPage{}, err
+	}
+
+	var page model.TransactionPage
+	err = b.db.View(func(btx *bbolt.Tx) error {
+		p := prefix(string(address))
+		c := btx.Bucket(transactionOrderBucket).Cursor()
+		descending := query.Order == model.SortDescending
+
+		var k []byte
+		if descending {
+			// Start below the first key past the range and the cursor.
+			end := join([]byte(address), []byte{1})
+			if query.ToBlock != 0 && query.ToBlock != math.MaxUint64 {
+				end = join(p, uint64Key(query.ToBlock+1))
+			}
+			if key := orderKey(address, after); hasCursor && bytes.Compare(key, end) < 0 {
+				end = key
+			}
+
+			if k, _ = c.Seek(end); k == nil {
+				k, _ = c.Last()
+			} else {
+				k, _ = c.Prev()
+			}
+		} else {
+			start := join(p, uint64Key(query.FromBlock))
+			if key := orderKey(address, after); hasCursor && bytes.Compare(key, start) > 0 {
+				start = key
+			}
+
+			k, _ = c.Seek(start)
+		}
+
+		page, err = query.Scan(address, func() (model.Transaction, bool, error) {
+			if k == nil || !bytes.HasPrefix(k, p) {
+				return model.Transaction{}, false, nil
+			}
+
+			blockNumber := binary.BigEndian.Uint64(k[len(p):])
+			if blockNumber < query.FromBlock || (query.ToBlock != 0 && blockNumber > query.ToBlock) {
+				return model.Transaction{}, false, nil
+			}
+
+			hash := k[len(p)+16:]
+			if descending {
+				k, _ = c.Prev()
+			} else {
+				k, _ = c.Next()
+			}
+
+			tx, ok, err := transactions.get(btx, address, hash)
+			if err != nil {
+				return model.Transaction{}, false, err
+			}
+			if !ok {
+				return model.Transaction{}, false, fmt.Errorf("transaction %s is indexed but not stored", hash)
+			}
+
+			return tx, true, nil
+		})
+
+		return err
+	})
+
+	return page, err
+}
+
+func (b *Bolt) RemoveTransaction(_ context.Context, address model.Address, hash string) error {
+	return b.db.Update(func(btx *bbolt.Tx) error {
+		return removeTransaction(btx, address, hash)
+	})
+}
+
+// removeTransaction deletes the transaction stored for address under hash and
+// drops address from the holders of the hash.
+func removeTransaction(btx *bbolt.Tx, address model.Address, hash string) error {
+	stored, ok, err := transactions.get(btx, address, []byte(hash))
+	if err != nil || !ok {
+		return err
+	}
+
+	if _, err := transactions.remove(btx, address, []byte(hash)); err != nil {
+		return err
+	}
+
+	if err := btx.Bucket(transactionOrderBucket).Delete(orderKey(address, model.PositionOf(stored))); err != nil {
+		return err
+	}
+
+	return btx.Bucket(holdersBucket).Delete(join(prefix(hash), []byte(address)))
+}
+
+func (b *Bolt) UpdateTransactionStatus(_ context.Context, address model.Address, hash string, status model.TransactionStatus) error {
+	return b.db.Update(func(btx *bbolt.Tx) error {
+		return updateTransactionStatus(btx, address, hash, status)
+	})
+}
+
+func updateTransactionStatus(btx *bbolt.Tx, address model.Address, hash string, status model.TransactionStatus) error {
+	return transactions.update(btx, address, []byte(hash), func(tx *model.Transaction) {
+		tx.Status = status
+	})
+}
+
+func (b *Bolt) AddTokenTransfer(_ context.Context, address model.Address, transfer model.TokenTransfer) error {
+	return b.db.Update(func(tx *bbolt.Tx) error {
+		return tokenTransfers.put(tx, address, transferKey(transfer.TransactionHash, transfer.LogIndex), transfer)
+	})
+}
+
+func (b *Bolt) GetTokenTransfers(_ context.Context, address model.Address) ([]model.TokenTransfer, error) {
+	var transfers []model.TokenTransfer
+	err := b.db.View(func(tx *bbolt.Tx) error {
+		var err error
+		transfers, err = tokenTransfers.list(tx, address)
+		return err
+	})
+
+	return transfers, err
+}
+
+func (b *Bolt) RemoveTokenTransfer(_ context.Context, address model.Address, hash string, logIndex uint64) error {
+	return b.db.Update(func(tx *bbolt.Tx) error {
+		_, err := tokenTransfers.remove(tx, address, transferKey(hash, logIndex))
+		return err
+	})
+}
+
+func (b *Bolt) UpdateTokenTransferStatus(_ context.Context, address model.Address, hash string, logIndex uint64, status model.TransactionStatus) error {
+	return b.db.Update(func(tx *bbolt.Tx) error {
+		return updateTokenTransferStatus(tx, address, hash, logIndex, status)
+	})
+}
+
+func updateTokenTransferStatus(tx *bbolt.Tx, address model.Address, hash string, logIndex uint64, status model.TransactionStatus) error {
+	return tokenTransfers.update(tx, address, transferKey(hash, logIndex), func(transfer *model.TokenTransfer) {
+		transfer.Status = status
+	})
+}
+
+func (b *Bolt) AddInternalTransfer(_ context.Context, address model.Address, transfer model.InternalTransfer) error {
+	return b.db.Update(func(tx *bbolt.Tx) error {
+		return internalTransfers.put(tx, address, transferKey(transfer.TransactionHash, transfer.Index), transfer)
+	})
+}
+
+func (b *Bolt) GetInternalTransfers(_ context.Context, address model.Address) ([]model.InternalTransfer, error) {
+	var transfers []model.InternalTransfer
+	err := b.db.View(func(tx *bbolt.Tx) error {
+		var err error
+		transfers, err = internalTransfers.list(tx, address)
+		return err
+	})
+
+	return transfers, err
+}
+
+func (b *Bolt) RemoveInternalTransfer(_ context.Context, address model.Address, hash string, index uint64) error {
+	return b.db.Update(func(tx *bbolt.Tx) error {
+		_, err := internalTransfers.remove(tx, address, transferKey(hash, index))
+		return err
+	})
+}
+
+func (b *Bolt) UpdateInternalTransferStatus(_ context.Context, address model.Address, hash string, index uint64, status model.TransactionStatus) error {
+	return b.db.Update(func(tx *bbolt.Tx) error {
+		return updateInternalTransferStatus(tx, address, hash, index, status)
+	})
+}
+
+func updateInternalTransferStatus(tx *bbolt.Tx, address model.Address, hash string, index uint64, status model.TransactionStatus) error {
+	return internalTransfers.update(tx, address, transferKey(hash, index), func(transfer *model.InternalTransfer) {
+		transfer.Status = status
+	})
+}
+
+// CommitBatch applies the batch in a single database transaction, which bbolt
+// rolls back if any write fails. Nothing is applied if ctx is already done.
+func (b *Bolt) CommitBatch(ctx context.Context, batch storage.Batch) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return b.db.Update(func(tx *bbolt.Tx) error {
+		for _, key := range batch.RemovedTransactions {
+			if err := removeTransaction(tx, key.Address, key.Hash); err != nil {
+				return err
+			}
+		}
+
+		for _, key := range batch.RemovedTokenTransfers {
+			if _, err := tokenTransfers.remove(tx, key.Address, transferKey(key.Hash, key.Index)); err != nil {
+				return err
+			}
+		}
+
+		for _, key := range batch.RemovedInternalTransfers {
+			if _, err := internalTransfers.remove(tx, key.Address, transferKey(key.Hash, key.Index)); err != nil {
+				return err
+			}
+		}
+
+		for _, entry := range batch.Transactions {
+			if err := putTransaction(tx, entry.Address, entry.Transaction); err != nil {
+				return err
+			}
+		}
+
+		for _, entry := range batch.TokenTransfers {
+			if err := tokenTransfers.put(tx, entry.Address, transferKey(entry.Transfer.TransactionHash, entry.Transfer.LogIndex), entry.Transfer); err != nil {
+				return err
+			}
+		}
+
+		for _, entry := range batch.InternalTransfers {
+			if err := internalTransfers.put(tx, entry.Address, transferKey(entry.Transfer.TransactionHash, entry.Transfer.Index), entry.Transfer); err != nil {
+				return err
+			}
+		}
+
+		for _, update := range batch.TransactionStatuses {
+			if err := updateTransactionStatus(tx, update.Address, update.Hash, update.Status); err != nil {
+				return err
+			}
+		}
+
+		for _, update := range batch.TokenTransferStatuses {
+			if err := updateTokenTransferStatus(tx, update.Address, update.Hash, update.Index, update.Status); err != nil {
+				return err
+			}
+		}
+
+		for _, update := range batch.InternalTransferStatuses {
+			if err := updateInternalTransferStatus(tx, update.Address, update.Hash, update.Index, update.Status); err != nil {
+				return err
+			}
+		}
+
+		if batch.Checkpoint != nil {
+			return putCheckpoint(tx, *batch.Checkpoint)
+		}
+
+		return nil
+	})
+}
+
+func (b *Bolt) SaveCheckpoint(_ context.Context, checkpoint model.Checkpoint) error {
+	return b.db.Update(func(tx *bbolt.Tx) error {
+		return putCheckpoint(tx, checkpoint)
+	})
+}
+
+func putCheckpoint(tx *bbolt.Tx, checkpoint model.Checkpoint) error {
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+
+	return tx.Bucket(metaBucket).Put(checkpointKey, data)
+}
+
+func (b *Bolt) LoadCheckpoint(_ context.Context) (model.Checkpoint, error) {
+	var checkpoint model.Checkpoint
+	err := b.db.View(func(tx *bbolt.Tx) error {
+		data := tx.Bucket(metaBucket).Get(checkpointKey)
+		if data == nil {
+			return storage.ErrNoCheckpoint
+		}
+
+		return json.Unmarshal(data, &checkpoint)
+	})
+
+	return checkpoint, err
+}
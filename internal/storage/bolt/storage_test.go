@@ -0,0 +1,161 @@
+package bolt_test
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"trustwallet/internal/model"
+	"trustwallet/internal/storage"
+	"trustwallet/internal/storage/bolt"
+	"trustwallet/internal/storage/storagetest"
+)
+
+func open(t *testing.T, path string) *bolt.Bolt {
+	t.Helper()
+
+	b, err := bolt.Open(path)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	return b
+}
+
+func TestBolt(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storagetest.Storage {
+		b := open(t, filepath.Join(t.TempDir(), "parser.db"))
+		t.Cleanup(func() { b.Close() })
+
+		return b
+	})
+}
+
+func TestBolt_Reopen(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "parser.db")
+
+	address := model.Address("0xAddress1")
+	tx := model.Transaction{Hash: "0xTxHash1", From: address, To: "0xAddress2", Value: big.NewInt(100), BlockNumber: 100, Status: model.TransactionStatusPending}
+	transfer := model.TokenTransfer{Standard: model.TokenStandardERC20, Token: "0xToken", From: address, To: "0xAddress2", Amount: big.NewInt(5), TransactionHash: "0xTxHash1", LogIndex: 1, BlockNumber: 100}
+	checkpoint := model.Checkpoint{BlockNumber: 100, BlockHash: "0xBlock100"}
+
+	b := open(t, path)
+	if err := b.AddAddress(ctx, address); err != nil {
+		t.Fatalf("AddAddress() error = %v", err)
+	}
+
+	var batch storage.Batch
+	batch.AddTransaction(address, tx)
+	batch.AddTokenTransfer(address, transfer)
+	batch.SaveCheckpoint(checkpoint)
+	if err := b.CommitBatch(ctx, batch); err != nil {
+		t.Fatalf("CommitBatch() error = %v", err)
+	}
+
+	if err := b.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	// A fresh handle simulates a restart reading the database back.
+	b = open(t, path)
+	defer b.Close()
+
+	if subscribed, err := b.IsSubscribed(ctx, address); err != nil || !subscribed {
+		t.Errorf("IsSubscribed() = %v, %v, want true", subscribed, err)
+	}
+	if got, err := b.GetTransactions(ctx, address); err != nil || !reflect.DeepEqual(got, []model.Transaction{tx}) {
+		t.Errorf("GetTransactions() = %v, %v, want %v", got, err, []model.Transaction{tx})
+	}
+	if got, err := b.GetTransactionByHash(ctx, tx.Hash); err != nil || !reflect.DeepEqual(got, tx) {
+		t.Errorf("GetTransactionByHash() = %v, %v, want %v", got, err, tx)
+	}
+	if got, err := b.GetTokenTransfers(ctx, address); err != nil || !reflect.DeepEqual(got, []model.TokenTransfer{transfer}) {
+		t.Errorf("GetTokenTransfers() = %v, %v, want %v", got, err, []model.TokenTransfer{transfer})
+	}
+	if got, err := b.LoadCheckpoint(ctx); err != nil || !reflect.DeepEqual(got, checkpoint) {
+		t.Errorf("LoadCheckpoint() = %v, %v, want %v", got, err, checkpoint)
+	}
+}
+
+// TestBolt_QueryTransactions pages through every query shape and checks the
+// indexed walk returns what model.TransactionQuery.Apply does.
+func TestBolt_QueryTransactions(t *testing.T) {
+	ctx := context.Background()
+	b := open(t, filepath.Join(t.TempDir(), "parser.db"))
+	defer b.Close()
+
+	address := model.Address("0xAddress1")
+	other := model.Address("0xAddress2")
+
+	var txs []model.Transaction
+	for i := uint64(0); i < 30; i++ {
+		tx := model.Transaction{Hash: fmt.Sprintf("0xTxHash%02d", i), From: other, To: address, Value: big.NewInt(int64(i)), BlockNumber: 100 + i/3, TransactionIndex: 2 - i%3}
+		if i%4 == 0 {
+			tx.From, tx.To = address, other
+		}
+
+		txs = append(txs, tx)
+		if err := b.AddTransaction(ctx, address, tx); err != nil {
+			t.Fatalf("AddTransaction() error = %v", err)
+		}
+	}
+
+	// Another address's transactions sort right after this one's.
+	if err := b.AddTransaction(ctx, address+"0", model.Transaction{Hash: "0xOther", BlockNumber: 105}); err != nil {
+		t.Fatalf("AddTransaction() error = %v", err)
+	}
+
+	// Moving a transaction to another block must move its index entry too.
+	moved := txs[0]
+	moved.BlockNumber = 120
+	txs[0] = moved
+	if err := b.AddTransaction(ctx, address, moved); err != nil {
+		t.Fatalf("AddTransaction() error = %v", err)
+	}
+
+	if err := b.RemoveTransaction(ctx, address, txs[5].Hash); err != nil {
+		t.Fatalf("RemoveTransaction() error = %v", err)
+	}
+	txs = append(txs[:5], txs[6:]...)
+
+	queries := []model.TransactionQuery{
+		{},
+		{FromBlock: 103, ToBlock: 106},
+		{FromBlock: 103},
+		{ToBlock: 104},
+		{Direction: model.DirectionOutgoing},
+		{MinValue: big.NewInt(10)},
+		{FromBlock: 200},
+	}
+
+	for _, query := range queries {
+		for _, order := range []model.SortOrder{model.SortAscending, model.SortDescending} {
+			for _, limit := range []int{1, 4, 100} {
+				query.Order, query.Limit, query.Cursor = order, limit, ""
+
+				for page := 0; ; page++ {
+					want, err := query.Apply(address, txs)
+					if err != nil {
+						t.Fatalf("Apply(%+v) error = %v", query, err)
+					}
+
+					got, err := b.QueryTransactions(ctx, address, query)
+					if err != nil {
+						t.Fatalf("QueryTransactions(%+v) error = %v", query, err)
+					}
+					if !reflect.DeepEqual(got, want) {
+						t.Fatalf("QueryTransactions(%+v) page %d = %+v, want %+v", query, page, got, want)
+					}
+
+					if got.NextCursor == "" {
+						break
+					}
+					query.Cursor = got.NextCursor
+				}
+			}
+		}
+	}
+}
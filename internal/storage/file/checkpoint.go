@@ -0,0 +1,79 @@
+package file
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"trustwallet/internal/model"
+	"trustwallet/internal/storage"
+)
+
+// CheckpointStore keeps the parser checkpoint as a JSON file. Writes go to a
+// temporary file that is renamed over the original, so a crash never leaves
+// a partially written checkpoint behind.
+type CheckpointStore struct {
+	path string
+	mu   *sync.Mutex
+}
+
+func NewCheckpointStore(path string) *CheckpointStore {
+	return &CheckpointStore{
+		path: path,
+		mu:   &sync.Mutex{},
+	}
+}
+
+func (s *CheckpointStore) SaveCheckpoint(_ context.Context, checkpoint model.Checkpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), s.path)
+}
+
+func (s *CheckpointStore) LoadCheckpoint(_ context.Context) (model.Checkpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return model.Checkpoint{}, storage.ErrNoCheckpoint
+	}
+	if err != nil {
+		return model.Checkpoint{}, err
+	}
+
+	var checkpoint model.Checkpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return model.Checkpoint{}, err
+	}
+
+	return checkpoint, nil
+}
@@ -0,0 +1,68 @@
+package file_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"trustwallet/internal/model"
+	"trustwallet/internal/storage"
+	"trustwallet/internal/storage/file"
+)
+
+func TestCheckpointStore_NoCheckpoint(t *testing.T) {
+	store := file.NewCheckpointStore(filepath.Join(t.TempDir(), "checkpoint.json"))
+
+	_, err := store.LoadCheckpoint(context.Background())
+	if !errors.Is(err, storage.ErrNoCheckpoint) {
+		t.Errorf("LoadCheckpoint() error = %v, want %v", err, storage.ErrNoCheckpoint)
+	}
+}
+
+func TestCheckpointStore_SaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+
+	checkpoints := []model.Checkpoint{
+		{BlockNumber: 99, BlockHash: "0xBlock99"},
+		{BlockNumber: 100, BlockHash: "0xBlock100", Pending: []model.PendingBlock{
+			{Number: 100, Hash: "0xBlock100", ParentHash: "0xBlock99", Status: model.TransactionStatusPending, Transactions: []model.ActivityKey{{Address: "0xAddress1", Hash: "0xTxHash1"}}},
+		}},
+	}
+
+	for _, want := range checkpoints {
+		if err := file.NewCheckpointStore(path).SaveCheckpoint(context.Background(), want); err != nil {
+			t.Fatalf("SaveCheckpoint() error = %v", err)
+		}
+
+		// A fresh store simulates a restart reading the file back.
+		got, err := file.NewCheckpointStore(path).LoadCheckpoint(context.Background())
+		if err != nil {
+			t.Fatalf("LoadCheckpoint() error = %v", err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("LoadCheckpoint() = %v, want %v", got, want)
+		}
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatalf("ReadDir() error = %v", err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected only the checkpoint file to remain, got %d entries", len(entries))
+	}
+}
+
+func TestCheckpointStore_Corrupted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+	if err := os.WriteFile(path, []byte("not json"), 0o644); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	_, err := file.NewCheckpointStore(path).LoadCheckpoint(context.Background())
+	if err == nil || errors.Is(err, storage.ErrNoCheckpoint) {
+		t.Errorf("LoadCheckpoint() error = %v, want a decoding error", err)
+	}
+}
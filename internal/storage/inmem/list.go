@@ -0,0 +1,111 @@
+package inmem
+
+// transferKey identifies a transfer within the transactions of an address: the
+// transaction that made it and its log or call frame index.
+type transferKey struct {
+	hash  string
+	index uint64
+}
+
+// keyedList is an insertion-ordered list holding at most one item per key.
+// Removed items leave a tombstone, so removal is O(1); the tombstones are
+// compacted away once they make up half of the list.
+type keyedList[K comparable, V any] struct {
+	entries   []keyedEntry[V]
+	positions map[K]int
+	removed   int
+}
+
+type keyedEntry[V any] struct {
+	item V
+	live bool
+}
+
+func newKeyedList[K comparable, V any]() *keyedList[K, V] {
+	return &keyedList[K, V]{positions: make(map[K]int)}
+}
+
+// put replaces the item stored under key in place, or appends it.
+func (l *keyedList[K, V]) put(key K, item V) {
+	if i, ok := l.positions[key]; ok {
+		l.entries[i].item = item
+		return
+	}
+
+	l.positions[key] = len(l.entries)
+	l.entries = append(l.entries, keyedEntry[V]{item: item, live: true})
+}
+
+func (l *keyedList[K, V]) get(key K) (V, bool) {
+	i, ok := l.positions[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+
+	return l.entries[i].item, true
+}
+
+// update applies fn to the item stored under key, if any.
+func (l *keyedList[K, V]) update(key K, fn func(*V)) {
+	if i, ok := l.positions[key]; ok {
+		fn(&l.entries[i].item)
+	}
+}
+
+// remove deletes the item stored under key and reports whether there was one.
+func (l *keyedList[K, V]) remove(key K) bool {
+	i, ok := l.positions[key]
+	if !ok {
+		return false
+	}
+
+	delete(l.positions, key)
+	l.entries[i] = keyedEntry[V]{}
+	l.removed++
+
+	if l.removed > len(l.entries)/2 {
+		l.compact()
+	}
+
+	return true
+}
+
+// compact drops the tombstones and moves the remaining items up.
+func (l *keyedList[K, V]) compact() {
+	positions := make([]K, len(l.entries))
+	for key, i := range l.positions {
+		positions[i] = key
+	}
+
+	live := 0
+	for i, entry := range l.entries {
+		if !entry.live {
+			continue
+		}
+
+		l.entries[live] = entry
+		l.positions[positions[i]] = live
+		live++
+	}
+
+	clear(l.entries[live:])
+	l.entries = l.entries[:live]
+	l.removed = 0
+}
+
+// list returns a copy of the items in insertion order.
+func (l *keyedList[K, V]) list() []V {
+	if l == nil {
+		return []V{}
+	}
+
+	items := make([]V, 0, len(l.entries)-l.removed)
+	for _, entry := range l.entries {
+		if entry.live {
+			items = append(items, entry.item)
+		}
+	}
+
+	return items
+}
@@ -1,59 +1,419 @@
 package inmem
 
 import (
+	"context"
+	"slices"
+	"strings"
 	"sync"
+	"time"
 	"trustwallet/internal/model"
+	"trustwallet/internal/storage"
 )
 
+// InMemory keeps everything in maps. Transactions are keyed by address and
+// hash and transfers by address, hash and index, so storing an entry again
+// replaces it instead of adding a duplicate.
 type InMemory struct {
-	subscribedAddresses map[model.Address]bool
-	transactions        map[model.Address][]model.Transaction
-	mu                  *sync.RWMutex
+	subscribedAddresses map[model.Address]model.Subscription
+	transactions        map[model.Address]*keyedList[string, model.Transaction]
+	tokenTransfers      map[model.Address]*keyedList[transferKey, model.TokenTransfer]
+	internalTransfers   map[model.Address]*keyedList[transferKey, model.InternalTransfer]
+	// holders indexes the addresses storing each transaction hash.
+	holders    map[string]map[model.Address]struct{}
+	checkpoint *model.Checkpoint
+	mu         *sync.RWMutex
 }
 
 func New() *InMemory {
 	return &InMemory{
-		subscribedAddresses: make(map[model.Address]bool),
-		transactions:        make(map[model.Address][]model.Transaction),
+		subscribedAddresses: make(map[model.Address]model.Subscription),
+		transactions:        make(map[model.Address]*keyedList[string, model.Transaction]),
+		tokenTransfers:      make(map[model.Address]*keyedList[transferKey, model.TokenTransfer]),
+		internalTransfers:   make(map[model.Address]*keyedList[transferKey, model.InternalTransfer]),
+		holders:             make(map[string]map[model.Address]struct{}),
 		mu:                  &sync.RWMutex{},
 	}
 }
 
-func (im *InMemory) AddAddress(address model.Address) error {
+func (im *InMemory) AddAddress(ctx context.Context, address model.Address) error {
+	return im.AddSubscription(ctx, model.Subscription{Address: address, CreatedAt: time.Now().UTC()})
+}
+
+func (im *InMemory) AddSubscription(_ context.Context, subscription model.Subscription) error {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	if existing, ok := im.subscribedAddresses[subscription.Address]; ok {
+		subscription.CreatedAt = existing.CreatedAt
+	}
+
+	im.subscribedAddresses[subscription.Address] = subscription
+
+	return nil
+}
+
+func (im *InMemory) RemoveSubscription(_ context.Context, address model.Address, purge bool) error {
 	im.mu.Lock()
 	defer im.mu.Unlock()
 
-	im.subscribedAddresses[address] = true
+	if _, ok := im.subscribedAddresses[address]; !ok {
+		return storage.ErrNotSubscribed
+	}
+
+	delete(im.subscribedAddresses, address)
+
+	if purge {
+		for _, tx := range im.transactions[address].list() {
+			im.removeHolder(tx.Hash, address)
+		}
+
+		delete(im.transactions, address)
+		delete(im.tokenTransfers, address)
+		delete(im.internalTransfers, address)
+	}
 
 	return nil
 }
 
-func (im *InMemory) IsSubscribed(address model.Address) (bool, error) {
+func (im *InMemory) IsSubscribed(_ context.Context, address model.Address) (bool, error) {
+	im.mu.RLock()
+	defer im.mu.RUnlock()
+
+	subscription, ok := im.subscribedAddresses[address]
+
+	return ok && !subscription.Expired(time.Now()), nil
+}
+
+func (im *InMemory) ListAddresses(_ context.Context) ([]model.Address, error) {
+	im.mu.RLock()
+	defer im.mu.RUnlock()
+
+	now := time.Now()
+
+	addresses := make([]model.Address, 0, len(im.subscribedAddresses))
+	for address, subscription := range im.subscribedAddresses {
+		if !subscription.Expired(now) {
+			addresses = append(addresses, address)
+		}
+	}
+
+	slices.Sort(addresses)
+
+	return addresses, nil
+}
+
+func (im *InMemory) ListSubscriptions(_ context.Context, after model.Address, limit int) ([]model.Subscription, error) {
 	im.mu.RLock()
 	defer im.mu.RUnlock()
 
-	subscribed := im.subscribedAddresses[address]
+	subscriptions := make([]model.Subscription, 0, len(im.subscribedAddresses))
+	for address, subscription := range im.subscribedAddresses {
+		if address > after {
+			subscriptions = append(subscriptions, subscription)
+		}
+	}
+
+	slices.SortFunc(subscriptions, func(a, b model.Subscription) int {
+		return strings.Compare(string(a.Address), string(b.Address))
+	})
 
-	return subscribed, nil
+	if limit > 0 && len(subscriptions) > limit {
+		subscriptions = subscriptions[:limit]
+	}
+
+	return subscriptions, nil
 }
 
-func (im *InMemory) AddTransaction(address model.Address, tx model.Transaction) error {
+func (im *InMemory) AddTransaction(_ context.Context, address model.Address, tx model.Transaction) error {
 	im.mu.Lock()
 	defer im.mu.Unlock()
 
-	im.transactions[address] = append(im.transactions[address], tx)
+	im.putTransaction(address, tx)
 
 	return nil
 }
 
-func (im *InMemory) GetTransactions(address model.Address) ([]model.Transaction, error) {
+// putTransaction stores tx for address. The caller must hold mu.
+func (im *InMemory) putTransaction(address model.Address, tx model.Transaction) {
+	txs, ok := im.transactions[address]
+	if !ok {
+		txs = newKeyedList[string, model.Transaction]()
+		im.transactions[address] = txs
+	}
+
+	txs.put(tx.Hash, tx)
+
+	if im.holders[tx.Hash] == nil {
+		im.holders[tx.Hash] = make(map[model.Address]struct{})
+	}
+	im.holders[tx.Hash][address] = struct{}{}
+}
+
+func (im *InMemory) GetTransactions(_ context.Context, address model.Address) ([]model.Transaction, error) {
 	im.mu.RLock()
 	defer im.mu.RUnlock()
 
-	txs, ok := im.transactions[address]
+	return im.transactions[address].list(), nil
+}
+
+func (im *InMemory) GetTransactionByHash(_ context.Context, hash string) (model.Transaction, error) {
+	im.mu.RLock()
+	defer im.mu.RUnlock()
+
+	for address := range im.holders[hash] {
+		if tx, ok := im.transactions[address].get(hash); ok {
+			return tx, nil
+		}
+	}
+
+	return model.Transaction{}, storage.ErrNotFound
+}
+
+func (im *InMemory) QueryTransactions(_ context.Context, address model.Address, query model.TransactionQuery) (model.TransactionPage, error) {
+	im.mu.RLock()
+	defer im.mu.RUnlock()
+
+	return query.Apply(address, im.transactions[address].list())
+}
+
+func (im *InMemory) RemoveTransaction(_ context.Context, address model.Address, hash string) error {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	im.removeTransaction(address, hash)
+
+	return nil
+}
+
+// removeTransaction deletes the transaction stored for address under hash.
+// The caller must hold mu.
+func (im *InMemory) removeTransaction(address model.Address, hash string) {
+	if txs, ok := im.transactions[address]; ok && txs.remove(hash) {
+		im.removeHolder(hash, address)
+	}
+}
+
+func (im *InMemory) UpdateTransactionStatus(_ context.Context, address model.Address, hash string, status model.TransactionStatus) error {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	im.updateTransactionStatus(address, hash, status)
+
+	return nil
+}
+
+// updateTransactionStatus sets the status of a stored transaction. The caller
+// must hold mu.
+func (im *InMemory) updateTransactionStatus(address model.Address, hash string, status model.TransactionStatus) {
+	if txs, ok := im.transactions[address]; ok {
+		txs.update(hash, func(tx *model.Transaction) {
+			tx.Status = status
+		})
+	}
+}
+
+func (im *InMemory) AddTokenTransfer(_ context.Context, address model.Address, transfer model.TokenTransfer) error {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	im.putTokenTransfer(address, transfer)
+
+	return nil
+}
+
+// putTokenTransfer stores transfer for address. The caller must hold mu.
+func (im *InMemory) putTokenTransfer(address model.Address, transfer model.TokenTransfer) {
+	transfers, ok := im.tokenTransfers[address]
+	if !ok {
+		transfers = newKeyedList[transferKey, model.TokenTransfer]()
+		im.tokenTransfers[address] = transfers
+	}
+
+	transfers.put(transferKey{hash: transfer.TransactionHash, index: transfer.LogIndex}, transfer)
+}
+
+func (im *InMemory) GetTokenTransfers(_ context.Context, address model.Address) ([]model.TokenTransfer, error) {
+	im.mu.RLock()
+	defer im.mu.RUnlock()
+
+	return im.tokenTransfers[address].list(), nil
+}
+
+func (im *InMemory) RemoveTokenTransfer(_ context.Context, address model.Address, hash string, logIndex uint64) error {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	im.removeTokenTransfer(address, hash, logIndex)
+
+	return nil
+}
+
+// removeTokenTransfer deletes a stored token transfer. The caller must hold mu.
+func (im *InMemory) removeTokenTransfer(address model.Address, hash string, logIndex uint64) {
+	if transfers, ok := im.tokenTransfers[address]; ok {
+		transfers.remove(transferKey{hash: hash, index: logIndex})
+	}
+}
+
+func (im *InMemory) UpdateTokenTransferStatus(_ context.Context, address model.Address, hash string, logIndex uint64, status model.TransactionStatus) error {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	im.updateTokenTransferStatus(address, hash, logIndex, status)
+
+	return nil
+}
+
+// updateTokenTransferStatus sets the status of a stored token transfer. The
+// caller must hold mu.
+func (im *InMemory) updateTokenTransferStatus(address model.Address, hash string, logIndex uint64, status model.TransactionStatus) {
+	if transfers, ok := im.tokenTransfers[address]; ok {
+		transfers.update(transferKey{hash: hash, index: logIndex}, func(transfer *model.TokenTransfer) {
+			transfer.Status = status
+		})
+	}
+}
+
+func (im *InMemory) AddInternalTransfer(_ context.Context, address model.Address, transfer model.InternalTransfer) error {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	im.putInternalTransfer(address, transfer)
+
+	return nil
+}
+
+// putInternalTransfer stores transfer for address. The caller must hold mu.
+func (im *InMemory) putInternalTransfer(address model.Address, transfer model.InternalTransfer) {
+	transfers, ok := im.internalTransfers[address]
 	if !ok {
-		return []model.Transaction{}, nil
+		transfers = newKeyedList[transferKey, model.InternalTransfer]()
+		im.internalTransfers[address] = transfers
+	}
+
+	transfers.put(transferKey{hash: transfer.TransactionHash, index: transfer.Index}, transfer)
+}
+
+func (im *InMemory) GetInternalTransfers(_ context.Context, address model.Address) ([]model.InternalTransfer, error) {
+	im.mu.RLock()
+	defer im.mu.RUnlock()
+
+	return im.internalTransfers[address].list(), nil
+}
+
+func (im *InMemory) RemoveInternalTransfer(_ context.Context, address model.Address, hash string, index uint64) error {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	im.removeInternalTransfer(address, hash, index)
+
+	return nil
+}
+
+// removeInternalTransfer deletes a stored internal transfer. The caller must hold mu.
+func (im *InMemory) removeInternalTransfer(address model.Address, hash string, index uint64) {
+	if transfers, ok := im.internalTransfers[address]; ok {
+		transfers.remove(transferKey{hash: hash, index: index})
+	}
+}
+
+func (im *InMemory) UpdateInternalTransferStatus(_ context.Context, address model.Address, hash string, index uint64, status model.TransactionStatus) error {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	im.updateInternalTransferStatus(address, hash, index, status)
+
+	return nil
+}
+
+// updateInternalTransferStatus sets the status of a stored internal transfer. The
+// caller must hold mu.
+func (im *InMemory) updateInternalTransferStatus(address model.Address, hash string, index uint64, status model.TransactionStatus) {
+	if transfers, ok := im.internalTransfers[address]; ok {
+		transfers.update(transferKey{hash: hash, index: index}, func(transfer *model.InternalTransfer) {
+			transfer.Status = status
+		})
+	}
+}
+
+// CommitBatch applies the batch under a single lock, so readers see either
+// none or all of it. Nothing is applied if ctx is already done.
+func (im *InMemory) CommitBatch(ctx context.Context, batch storage.Batch) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	for _, key := range batch.RemovedTransactions {
+		im.removeTransaction(key.Address, key.Hash)
+	}
+
+	for _, key := range batch.RemovedTokenTransfers {
+		im.removeTokenTransfer(key.Address, key.Hash, key.Index)
+	}
+
+	for _, key := range batch.RemovedInternalTransfers {
+		im.removeInternalTransfer(key.Address, key.Hash, key.Index)
+	}
+
+	for _, entry := range batch.Transactions {
+		im.putTransaction(entry.Address, entry.Transaction)
+	}
+
+	for _, entry := range batch.TokenTransfers {
+		im.putTokenTransfer(entry.Address, entry.Transfer)
+	}
+
+	for _, entry := range batch.InternalTransfers {
+		im.putInternalTransfer(entry.Address, entry.Transfer)
+	}
+
+	for _, update := range batch.TransactionStatuses {
+		im.updateTransactionStatus(update.Address, update.Hash, update.Status)
+	}
+
+	for _, update := range batch.TokenTransferStatuses {
+		im.updateTokenTransferStatus(update.Address, update.Hash, update.Index, update.Status)
+	}
+
+	for _, update := range batch.InternalTransferStatuses {
+		im.updateInternalTransferStatus(update.Address, update.Hash, update.Index, update.Status)
+	}
+
+	if batch.Checkpoint != nil {
+		checkpoint := *batch.Checkpoint
+		im.checkpoint = &checkpoint
+	}
+
+	return nil
+}
+
+// removeHolder drops address from the holders of hash. The caller must hold mu.
+func (im *InMemory) removeHolder(hash string, address model.Address) {
+	delete(im.holders[hash], address)
+	if len(im.holders[hash]) == 0 {
+		delete(im.holders, hash)
+	}
+}
+
+func (im *InMemory) SaveCheckpoint(_ context.Context, checkpoint model.Checkpoint) error {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	im.checkpoint = &checkpoint
+
+	return nil
+}
+
+func (im *InMemory) LoadCheckpoint(_ context.Context) (model.Checkpoint, error) {
+	im.mu.RLock()
+	defer im.mu.RUnlock()
+
+	if im.checkpoint == nil {
+		return model.Checkpoint{}, storage.ErrNoCheckpoint
 	}
 
-	return txs, nil
+	return *im.checkpoint, nil
 }
@@ -3,8 +3,12 @@
 package mocks
 
 import (
+	context "context"
+
 	model "trustwallet/internal/model"
 
+	storage "trustwallet/internal/storage"
+
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -13,17 +17,71 @@ type Storage struct {
 	mock.Mock
 }
 
-// AddAddress provides a mock function with given fields: address
-func (_m *Storage) AddAddress(address model.Address) error {
-	ret := _m.Called(address)
+// AddAddress provides a mock function with given fields: ctx, address
+func (_m *Storage) AddAddress(ctx context.Context, address model.Address) error {
+	ret := _m.Called(ctx, address)
 
 	if len(ret) == 0 {
 		panic("no return value specified for AddAddress")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(model.Address) error); ok {
-		r0 = rf(address)
+	if rf, ok := ret.Get(0).(func(context.Context, model.Address) error); ok {
+		r0 = rf(ctx, address)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AddInternalTransfer provides a mock function with given fields: ctx, address, transfer
+func (_m *Storage) AddInternalTransfer(ctx context.Context, address model.Address, transfer model.InternalTransfer) error {
+	ret := _m.Called(ctx, address, transfer)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddInternalTransfer")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.Address, model.InternalTransfer) error); ok {
+		r0 = rf(ctx, address, transfer)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AddSubscription provides a mock function with given fields: ctx, subscription
+func (_m *Storage) AddSubscription(ctx context.Context, subscription model.Subscription) error {
+	ret := _m.Called(ctx, subscription)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddSubscription")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.Subscription) error); ok {
+		r0 = rf(ctx, subscription)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AddTokenTransfer provides a mock function with given fields: ctx, address, transfer
+func (_m *Storage) AddTokenTransfer(ctx context.Context, address model.Address, transfer model.TokenTransfer) error {
+	ret := _m.Called(ctx, address, transfer)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddTokenTransfer")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.Address, model.TokenTransfer) error); ok {
+		r0 = rf(ctx, address, transfer)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -31,17 +89,35 @@ func (_m *Storage) AddAddress(address model.Address) error {
 	return r0
 }
 
-// AddTransaction provides a mock function with given fields: address, tx
-func (_m *Storage) AddTransaction(address model.Address, tx model.Transaction) error {
-	ret := _m.Called(address, tx)
+// AddTransaction provides a mock function with given fields: ctx, address, tx
+func (_m *Storage) AddTransaction(ctx context.Context, address model.Address, tx model.Transaction) error {
+	ret := _m.Called(ctx, address, tx)
 
 	if len(ret) == 0 {
 		panic("no return value specified for AddTransaction")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(model.Address, model.Transaction) error); ok {
-		r0 = rf(address, tx)
+	if rf, ok := ret.Get(0).(func(context.Context, model.Address, model.Transaction) error); ok {
+		r0 = rf(ctx, address, tx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CommitBatch provides a mock function with given fields: ctx, batch
+func (_m *Storage) CommitBatch(ctx context.Context, batch storage.Batch) error {
+	ret := _m.Called(ctx, batch)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CommitBatch")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, storage.Batch) error); ok {
+		r0 = rf(ctx, batch)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -49,9 +125,97 @@ func (_m *Storage) AddTransaction(address model.Address, tx model.Transaction) e
 	return r0
 }
 
-// GetTransactions provides a mock function with given fields: address
-func (_m *Storage) GetTransactions(address model.Address) ([]model.Transaction, error) {
-	ret := _m.Called(address)
+// GetInternalTransfers provides a mock function with given fields: ctx, address
+func (_m *Storage) GetInternalTransfers(ctx context.Context, address model.Address) ([]model.InternalTransfer, error) {
+	ret := _m.Called(ctx, address)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetInternalTransfers")
+	}
+
+	var r0 []model.InternalTransfer
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.Address) ([]model.InternalTransfer, error)); ok {
+		return rf(ctx, address)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.Address) []model.InternalTransfer); ok {
+		r0 = rf(ctx, address)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.InternalTransfer)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.Address) error); ok {
+		r1 = rf(ctx, address)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTokenTransfers provides a mock function with given fields: ctx, address
+func (_m *Storage) GetTokenTransfers(ctx context.Context, address model.Address) ([]model.TokenTransfer, error) {
+	ret := _m.Called(ctx, address)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTokenTransfers")
+	}
+
+	var r0 []model.TokenTransfer
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.Address) ([]model.TokenTransfer, error)); ok {
+		return rf(ctx, address)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.Address) []model.TokenTransfer); ok {
+		r0 = rf(ctx, address)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.TokenTransfer)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.Address) error); ok {
+		r1 = rf(ctx, address)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTransactionByHash provides a mock function with given fields: ctx, hash
+func (_m *Storage) GetTransactionByHash(ctx context.Context, hash string) (model.Transaction, error) {
+	ret := _m.Called(ctx, hash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTransactionByHash")
+	}
+
+	var r0 model.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (model.Transaction, error)); ok {
+		return rf(ctx, hash)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) model.Transaction); ok {
+		r0 = rf(ctx, hash)
+	} else {
+		r0 = ret.Get(0).(model.Transaction)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, hash)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTransactions provides a mock function with given fields: ctx, address
+func (_m *Storage) GetTransactions(ctx context.Context, address model.Address) ([]model.Transaction, error) {
+	ret := _m.Called(ctx, address)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetTransactions")
@@ -59,19 +223,19 @@ func (_m *Storage) GetTransactions(address model.Address) ([]model.Transaction,
 
 	var r0 []model.Transaction
 	var r1 error
-	if rf, ok := ret.Get(0).(func(model.Address) ([]model.Transaction, error)); ok {
-		return rf(address)
+	if rf, ok := ret.Get(0).(func(context.Context, model.Address) ([]model.Transaction, error)); ok {
+		return rf(ctx, address)
 	}
-	if rf, ok := ret.Get(0).(func(model.Address) []model.Transaction); ok {
-		r0 = rf(address)
+	if rf, ok := ret.Get(0).(func(context.Context, model.Address) []model.Transaction); ok {
+		r0 = rf(ctx, address)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]model.Transaction)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(model.Address) error); ok {
-		r1 = rf(address)
+	if rf, ok := ret.Get(1).(func(context.Context, model.Address) error); ok {
+		r1 = rf(ctx, address)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -79,9 +243,9 @@ func (_m *Storage) GetTransactions(address model.Address) ([]model.Transaction,
 	return r0, r1
 }
 
-// IsSubscribed provides a mock function with given fields: address
-func (_m *Storage) IsSubscribed(address model.Address) (bool, error) {
-	ret := _m.Called(address)
+// IsSubscribed provides a mock function with given fields: ctx, address
+func (_m *Storage) IsSubscribed(ctx context.Context, address model.Address) (bool, error) {
+	ret := _m.Called(ctx, address)
 
 	if len(ret) == 0 {
 		panic("no return value specified for IsSubscribed")
@@ -89,17 +253,105 @@ func (_m *Storage) IsSubscribed(address model.Address) (bool, error) {
 
 	var r0 bool
 	var r1 error
-	if rf, ok := ret.Get(0).(func(model.Address) (bool, error)); ok {
-		return rf(address)
+	if rf, ok := ret.Get(0).(func(context.Context, model.Address) (bool, error)); ok {
+		return rf(ctx, address)
 	}
-	if rf, ok := ret.Get(0).(func(model.Address) bool); ok {
-		r0 = rf(address)
+	if rf, ok := ret.Get(0).(func(context.Context, model.Address) bool); ok {
+		r0 = rf(ctx, address)
 	} else {
 		r0 = ret.Get(0).(bool)
 	}
 
-	if rf, ok := ret.Get(1).(func(model.Address) error); ok {
-		r1 = rf(address)
+	if rf, ok := ret.Get(1).(func(context.Context, model.Address) error); ok {
+		r1 = rf(ctx, address)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListAddresses provides a mock function with given fields: ctx
+func (_m *Storage) ListAddresses(ctx context.Context) ([]model.Address, error) {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListAddresses")
+	}
+
+	var r0 []model.Address
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context) ([]model.Address, error)); ok {
+		return rf(ctx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context) []model.Address); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Address)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListSubscriptions provides a mock function with given fields: ctx, after, limit
+func (_m *Storage) ListSubscriptions(ctx context.Context, after model.Address, limit int) ([]model.Subscription, error) {
+	ret := _m.Called(ctx, after, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListSubscriptions")
+	}
+
+	var r0 []model.Subscription
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.Address, int) ([]model.Subscription, error)); ok {
+		return rf(ctx, after, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.Address, int) []model.Subscription); ok {
+		r0 = rf(ctx, after, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.Subscription)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.Address, int) error); ok {
+		r1 = rf(ctx, after, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// QueryTransactions provides a mock function with given fields: ctx, address, query
+func (_m *Storage) QueryTransactions(ctx context.Context, address model.Address, query model.TransactionQuery) (model.TransactionPage, error) {
+	ret := _m.Called(ctx, address, query)
+
+	if len(ret) == 0 {
+		panic("no return value specified for QueryTransactions")
+	}
+
+	var r0 model.TransactionPage
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.Address, model.TransactionQuery) (model.TransactionPage, error)); ok {
+		return rf(ctx, address, query)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, model.Address, model.TransactionQuery) model.TransactionPage); ok {
+		r0 = rf(ctx, address, query)
+	} else {
+		r0 = ret.Get(0).(model.TransactionPage)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, model.Address, model.TransactionQuery) error); ok {
+		r1 = rf(ctx, address, query)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -107,6 +359,132 @@ func (_m *Storage) IsSubscribed(address model.Address) (bool, error) {
 	return r0, r1
 }
 
+// RemoveInternalTransfer provides a mock function with given fields: ctx, address, hash, index
+func (_m *Storage) RemoveInternalTransfer(ctx context.Context, address model.Address, hash string, index uint64) error {
+	ret := _m.Called(ctx, address, hash, index)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveInternalTransfer")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.Address, string, uint64) error); ok {
+		r0 = rf(ctx, address, hash, index)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RemoveSubscription provides a mock function with given fields: ctx, address, purge
+func (_m *Storage) RemoveSubscription(ctx context.Context, address model.Address, purge bool) error {
+	ret := _m.Called(ctx, address, purge)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveSubscription")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.Address, bool) error); ok {
+		r0 = rf(ctx, address, purge)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RemoveTokenTransfer provides a mock function with given fields: ctx, address, hash, logIndex
+func (_m *Storage) RemoveTokenTransfer(ctx context.Context, address model.Address, hash string, logIndex uint64) error {
+	ret := _m.Called(ctx, address, hash, logIndex)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveTokenTransfer")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.Address, string, uint64) error); ok {
+		r0 = rf(ctx, address, hash, logIndex)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RemoveTransaction provides a mock function with given fields: ctx, address, hash
+func (_m *Storage) RemoveTransaction(ctx context.Context, address model.Address, hash string) error {
+	ret := _m.Called(ctx, address, hash)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveTransaction")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.Address, string) error); ok {
+		r0 = rf(ctx, address, hash)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateInternalTransferStatus provides a mock function with given fields: ctx, address, hash, index, status
+func (_m *Storage) UpdateInternalTransferStatus(ctx context.Context, address model.Address, hash string, index uint64, status model.TransactionStatus) error {
+	ret := _m.Called(ctx, address, hash, index, status)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateInternalTransferStatus")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.Address, string, uint64, model.TransactionStatus) error); ok {
+		r0 = rf(ctx, address, hash, index, status)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateTokenTransferStatus provides a mock function with given fields: ctx, address, hash, logIndex, status
+func (_m *Storage) UpdateTokenTransferStatus(ctx context.Context, address model.Address, hash string, logIndex uint64, status model.TransactionStatus) error {
+	ret := _m.Called(ctx, address, hash, logIndex, status)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateTokenTransferStatus")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.Address, string, uint64, model.TransactionStatus) error); ok {
+		r0 = rf(ctx, address, hash, logIndex, status)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateTransactionStatus provides a mock function with given fields: ctx, address, hash, status
+func (_m *Storage) UpdateTransactionStatus(ctx context.Context, address model.Address, hash string, status model.TransactionStatus) error {
+	ret := _m.Called(ctx, address, hash, status)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateTransactionStatus")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, model.Address, string, model.TransactionStatus) error); ok {
+		r0 = rf(ctx, address, hash, status)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewStorage creates a new instance of Storage. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewStorage(t interface {
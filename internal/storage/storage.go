@@ -1,12 +1,68 @@
 package storage
 
-import "trustwallet/internal/model"
+import (
+	"context"
+	"errors"
+	"trustwallet/internal/model"
+)
 
+var (
+	ErrNoCheckpoint  = errors.New("no checkpoint stored")
+	ErrNotSubscribed = errors.New("address is not subscribed")
+	ErrNotFound      = errors.New("not found")
+)
+
+// Storage keeps the subscriptions and the activity recorded for them.
+// Transactions are keyed by address and hash, token transfers by address, hash
+// and log index, and internal transfers by address, hash and call index.
+// Adding an entry whose key is already stored replaces it, so re-processing a
+// block is idempotent.
+//
 //go:generate mockery --name=Storage --case=underscore --output=./mocks
 type Storage interface {
-	AddAddress(address model.Address) error
-	IsSubscribed(address model.Address) (bool, error)
+	AddAddress(ctx context.Context, address model.Address) error
+	// AddSubscription subscribes an address or updates the label and expiry of
+	// an existing subscription, which keeps its creation time.
+	AddSubscription(ctx context.Context, subscription model.Subscription) error
+	// RemoveSubscription returns ErrNotSubscribed if address is not subscribed.
+	// With purge set, the address's stored activity is deleted as well.
+	RemoveSubscription(ctx context.Context, address model.Address, purge bool) error
+	// IsSubscribed and ListAddresses leave out expired subscriptions.
+	IsSubscribed(ctx context.Context, address model.Address) (bool, error)
+	ListAddresses(ctx context.Context) ([]model.Address, error)
+	// ListSubscriptions returns up to limit subscriptions, expired ones
+	// included, ordered by address and starting after the given address. A
+	// limit of zero or less returns them all.
+	ListSubscriptions(ctx context.Context, after model.Address, limit int) ([]model.Subscription, error)
+
+	AddTransaction(ctx context.Context, address model.Address, tx model.Transaction) error
+	GetTransactions(ctx context.Context, address model.Address) ([]model.Transaction, error)
+	// GetTransactionByHash looks a transaction up across all addresses. It
+	// returns ErrNotFound if no address stores it.
+	GetTransactionByHash(ctx context.Context, hash string) (model.Transaction, error)
+	// QueryTransactions returns a page of the transactions of address matching
+	// query. It returns model.ErrInvalidCursor for a malformed cursor.
+	QueryTransactions(ctx context.Context, address model.Address, query model.TransactionQuery) (model.TransactionPage, error)
+	RemoveTransaction(ctx context.Context, address model.Address, hash string) error
+	UpdateTransactionStatus(ctx context.Context, address model.Address, hash string, status model.TransactionStatus) error
+
+	AddTokenTransfer(ctx context.Context, address model.Address, transfer model.TokenTransfer) error
+	GetTokenTransfers(ctx context.Context, address model.Address) ([]model.TokenTransfer, error)
+	RemoveTokenTransfer(ctx context.Context, address model.Address, hash string, logIndex uint64) error
+	UpdateTokenTransferStatus(ctx context.Context, address model.Address, hash string, logIndex uint64, status model.TransactionStatus) error
+
+	AddInternalTransfer(ctx context.Context, address model.Address, transfer model.InternalTransfer) error
+	GetInternalTransfers(ctx context.Context, address model.Address) ([]model.InternalTransfer, error)
+	RemoveInternalTransfer(ctx context.Context, address model.Address, hash string, index uint64) error
+	UpdateInternalTransferStatus(ctx context.Context, address model.Address, hash string, index uint64, status model.TransactionStatus) error
+
+	// CommitBatch applies every write in batch, or none of them if it fails.
+	CommitBatch(ctx context.Context, batch Batch) error
+}
 
-	AddTransaction(address model.Address, tx model.Transaction) error
-	GetTransactions(address model.Address) ([]model.Transaction, error)
+//go:generate mockery --name=CheckpointStore --case=underscore --output=./mocks
+type CheckpointStore interface {
+	SaveCheckpoint(ctx context.Context, checkpoint model.Checkpoint) error
+	// LoadCheckpoint returns ErrNoCheckpoint if nothing was saved yet.
+	LoadCheckpoint(ctx context.Context) (model.Checkpoint, error)
 }
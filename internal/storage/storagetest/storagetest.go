@@ -0,0 +1,826 @@
+// Package storagetest is a conformance suite for storage.Storage
+// implementations, so every backend behaves like the in-memory one.
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+	"trustwallet/internal/model"
+	"trustwallet/internal/storage"
+)
+
+// Storage is a storage that also keeps the parser checkpoint.
+type Storage interface {
+	storage.Storage
+	storage.CheckpointStore
+}
+
+// Run runs the suite against the storages newStorage returns, an empty one
+// for every test.
+func Run(t *testing.T, newStorage func(t *testing.T) Storage) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, newStorage func(t *testing.T) Storage)
+	}{
+		{"AddAddress_IsSubscribed", testAddAddressIsSubscribed},
+		{"AddTransaction_GetTransactions", testAddTransactionGetTransactions},
+		{"RemoveTransaction", testRemoveTransaction},
+		{"UpdateTransactionStatus", testUpdateTransactionStatus},
+		{"TokenTransfers", testTokenTransfers},
+		{"QueryTransactions", testQueryTransactions},
+		{"GetTransactions_ReturnsCopy", testGetTransactionsReturnsCopy},
+		{"Idempotent", testIdempotent},
+		{"GetTransactionByHash", testGetTransactionByHash},
+		{"ListAddresses", testListAddresses},
+		{"Subscriptions", testSubscriptions},
+		{"RemoveSubscription", testRemoveSubscription},
+		{"InternalTransfers", testInternalTransfers},
+		{"CommitBatch", testCommitBatch},
+		{"Checkpoint", testCheckpoint},
+		{"Concurrency", testConcurrency},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.test(t, newStorage)
+		})
+	}
+}
+
+func testAddAddressIsSubscribed(t *testing.T, newStorage func(t *testing.T) Storage) {
+	tests := []struct {
+		name       string
+		addresses  []model.Address // Addresses to add
+		checkAddr  model.Address   // Address to check
+		wantSubbed bool
+	}{
+		{
+			name:       "Subscribe to new address",
+			addresses:  []model.Address{"0xAddress1"},
+			checkAddr:  "0xAddress1",
+			wantSubbed: true,
+		},
+		{
+			name:       "Check unsubscribed address",
+			addresses:  []model.Address{},
+			checkAddr:  "0xAddress2",
+			wantSubbed: false,
+		},
+		{
+			name:       "Subscribe to existing address",
+			addresses:  []model.Address{"0xAddress1", "0xAddress1"},
+			checkAddr:  "0xAddress1",
+			wantSubbed: true,
+		},
+		{
+			name:       "Subscribe to empty address",
+			addresses:  []model.Address{""},
+			checkAddr:  "",
+			wantSubbed: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newStorage(t)
+
+			for _, addr := range tt.addresses {
+				err := s.AddAddress(context.Background(), addr)
+				if err != nil {
+					t.Errorf("AddAddress() error = %v", err)
+				}
+			}
+
+			got, err := s.IsSubscribed(context.Background(), tt.checkAddr)
+			if err != nil {
+				t.Errorf("IsSubscribed() error = %v", err)
+			}
+			if got != tt.wantSubbed {
+				t.Errorf("IsSubscribed() = %v, want %v", got, tt.wantSubbed)
+			}
+		})
+	}
+}
+
+func testAddTransactionGetTransactions(t *testing.T, newStorage func(t *testing.T) Storage) {
+	tests := []struct {
+		name             string
+		transactions     []model.Transaction
+		address          model.Address
+		wantTransactions []model.Transaction
+	}{
+		{
+			name: "Transactions for address1",
+			transactions: []model.Transaction{
+				{
+					Hash:        "0xTxHash1",
+					From:        "0xAddress1",
+					To:          "0xAddress2",
+					Value:       big.NewInt(100),
+					BlockNumber: 1,
+				},
+				{
+					Hash:        "0xTxHash2",
+					From:        "0xAddress2",
+					To:          "0xAddress1",
+					Value:       big.NewInt(200),
+					BlockNumber: 2,
+				},
+				{
+					Hash:        "0xTxHash3",
+					From:        "",
+					To:          "0xAddress1",
+					Value:       big.NewInt(300),
+					BlockNumber: 3,
+				},
+			},
+			address: "0xAddress1",
+			wantTransactions: []model.Transaction{
+				{
+					Hash:        "0xTxHash1",
+					From:        "0xAddress1",
+					To:          "0xAddress2",
+					Value:       big.NewInt(100),
+					BlockNumber: 1,
+				},
+				{
+					Hash:        "0xTxHash2",
+					From:        "0xAddress2",
+					To:          "0xAddress1",
+					Value:       big.NewInt(200),
+					BlockNumber: 2,
+				},
+				{
+					Hash:        "0xTxHash3",
+					From:        "",
+					To:          "0xAddress1",
+					Value:       big.NewInt(300),
+					BlockNumber: 3,
+				},
+			},
+		},
+		{
+			name: "Transactions for address2",
+			transactions: []model.Transaction{
+				{
+					Hash:        "0xTxHash1",
+					From:        "0xAddress1",
+					To:          "0xAddress2",
+					Value:       big.NewInt(100),
+					BlockNumber: 1,
+				},
+				{
+					Hash:        "0xTxHash2",
+					From:        "0xAddress2",
+					To:          "0xAddress1",
+					Value:       big.NewInt(200),
+					BlockNumber: 2,
+				},
+			},
+			address: "0xAddress2",
+			wantTransactions: []model.Transaction{
+				{
+					Hash:        "0xTxHash1",
+					From:        "0xAddress1",
+					To:          "0xAddress2",
+					Value:       big.NewInt(100),
+					BlockNumber: 1,
+				},
+				{
+					Hash:        "0xTxHash2",
+					From:        "0xAddress2",
+					To:          "0xAddress1",
+					Value:       big.NewInt(200),
+					BlockNumber: 2,
+				},
+			},
+		},
+		{
+			name:             "No transactions for address3",
+			transactions:     []model.Transaction{},
+			address:          "0xAddress3",
+			wantTransactions: []model.Transaction{},
+		},
+		{
+			name: "Transactions for empty address",
+			transactions: []model.Transaction{
+				{
+					Hash:        "0xTxHash3",
+					From:        "",
+					To:          "0xAddress1",
+					Value:       big.NewInt(300),
+					BlockNumber: 3,
+				},
+			},
+			address: "",
+			wantTransactions: []model.Transaction{
+				{
+					Hash:        "0xTxHash3",
+					From:        "",
+					To:          "0xAddress1",
+					Value:       big.NewInt(300),
+					BlockNumber: 3,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newStorage(t)
+
+			for _, tx := range tt.transactions {
+				err := s.AddTransaction(context.Background(), tx.From, tx)
+				if err != nil {
+					t.Errorf("AddTransaction() error = %v", err)
+				}
+				err = s.AddTransaction(context.Background(), tx.To, tx)
+				if err != nil {
+					t.Errorf("AddTransaction() error = %v", err)
+				}
+			}
+
+			got, err := s.GetTransactions(context.Background(), tt.address)
+			if err != nil {
+				t.Errorf("GetTransactions() error = %v", err)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.wantTransactions) {
+				t.Errorf("GetTransactions() = %v, want %v", got, tt.wantTransactions)
+			}
+		})
+	}
+}
+
+func testRemoveTransaction(t *testing.T, newStorage func(t *testing.T) Storage) {
+	s := newStorage(t)
+
+	address := model.Address("0xAddress1")
+	orphaned := model.Transaction{Hash: "0xTxHash1", From: address, To: "0xAddress2", Value: big.NewInt(100), BlockNumber: 1}
+	kept := model.Transaction{Hash: "0xTxHash2", From: address, To: "0xAddress2", Value: big.NewInt(200), BlockNumber: 2}
+
+	for _, tx := range []model.Transaction{orphaned, kept} {
+		if err := s.AddTransaction(context.Background(), address, tx); err != nil {
+			t.Fatalf("AddTransaction() error = %v", err)
+		}
+	}
+
+	if err := s.RemoveTransaction(context.Background(), address, orphaned.Hash); err != nil {
+		t.Errorf("RemoveTransaction() error = %v", err)
+	}
+	if err := s.RemoveTransaction(context.Background(), "0xUnknown", orphaned.Hash); err != nil {
+		t.Errorf("RemoveTransaction() for unknown address error = %v", err)
+	}
+
+	got, err := s.GetTransactions(context.Background(), address)
+	if err != nil {
+		t.Fatalf("GetTransactions() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, []model.Transaction{kept}) {
+		t.Errorf("GetTransactions() = %v, want %v", got, []model.Transaction{kept})
+	}
+
+	// Removing most of a longer list, out of order, keeps the rest in order
+	// and still finds, updates and replaces them.
+	var txs []model.Transaction
+	for i := 0; i < 10; i++ {
+		tx := model.Transaction{Hash: fmt.Sprintf("0xManyHash%d", i), From: address, To: "0xAddress2", Value: big.NewInt(int64(i)), BlockNumber: uint64(10 + i)}
+		if err := s.AddTransaction(context.Background(), address, tx); err != nil {
+			t.Fatalf("AddTransaction() error = %v", err)
+		}
+		txs = append(txs, tx)
+	}
+
+	for _, i := range []int{7, 0, 3, 9, 1, 5, 8} {
+		if err := s.RemoveTransaction(context.Background(), address, txs[i].Hash); err != nil {
+			t.Fatalf("RemoveTransaction() error = %v", err)
+		}
+	}
+
+	if err := s.UpdateTransactionStatus(context.Background(), address, txs[4].Hash, model.TransactionStatusFinalized); err != nil {
+		t.Fatalf("UpdateTransactionStatus() error = %v", err)
+	}
+	txs[4].Status = model.TransactionStatusFinalized
+
+	if err := s.AddTransaction(context.Background(), address, txs[0]); err != nil {
+		t.Fatalf("AddTransaction() error = %v", err)
+	}
+
+	want := []model.Transaction{kept, txs[2], txs[4], txs[6], txs[0]}
+	got, err = s.GetTransactions(context.Background(), address)
+	if err != nil {
+		t.Fatalf("GetTransactions() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetTransactions() after removing many = %v, want %v", got, want)
+	}
+
+	if got, err := s.GetTransactionByHash(context.Background(), txs[6].Hash); err != nil || !reflect.DeepEqual(got, txs[6]) {
+		t.Errorf("GetTransactionByHash() = %v, %v, want %v", got, err, txs[6])
+	}
+}
+
+func testUpdateTransactionStatus(t *testing.T, newStorage func(t *testing.T) Storage) {
+	s := newStorage(t)
+
+	address := model.Address("0xAddress1")
+	tx := model.Transaction{Hash: "0xTxHash1", From: address, To: "0xAddress2", Value: big.NewInt(100), BlockNumber: 1, Status: model.TransactionStatusPending}
+	other := model.Transaction{Hash: "0xTxHash2", From: address, To: "0xAddress2", Value: big.NewInt(200), BlockNumber: 2, Status: model.TransactionStatusPending}
+
+	for _, tx := range []model.Transaction{tx, other} {
+		if err := s.AddTransaction(context.Background(), address, tx); err != nil {
+			t.Fatalf("AddTransaction() error = %v", err)
+		}
+	}
+
+	before, _ := s.GetTransactions(context.Background(), address)
+
+	if err := s.UpdateTransactionStatus(context.Background(), address, tx.Hash, model.TransactionStatusFinalized); err != nil {
+		t.Errorf("UpdateTransactionStatus() error = %v", err)
+	}
+
+	got, err := s.GetTransactions(context.Background(), address)
+	if err != nil {
+		t.Fatalf("GetTransactions() error = %v", err)
+	}
+	if got[0].Status != model.TransactionStatusFinalized {
+		t.Errorf("Status = %v, want %v", got[0].Status, model.TransactionStatusFinalized)
+	}
+	if got[1].Status != model.TransactionStatusPending {
+		t.Errorf("Status of untouched transaction = %v, want %v", got[1].Status, model.TransactionStatusPending)
+	}
+	if before[0].Status != model.TransactionStatusPending {
+		t.Errorf("previously returned slice was modified")
+	}
+}
+
+func testTokenTransfers(t *testing.T, newStorage func(t *testing.T) Storage) {
+	s := newStorage(t)
+
+	address := model.Address("0xAddress1")
+	first := model.TokenTransfer{Standard: model.TokenStandardERC20, Token: "0xToken", From: address, To: "0xAddress2", Amount: big.NewInt(100), TransactionHash: "0xTxHash1", LogIndex: 0, Status: model.TransactionStatusPending}
+	second := model.TokenTransfer{Standard: model.TokenStandardERC20, Token: "0xToken", From: address, To: "0xAddress3", Amount: big.NewInt(200), TransactionHash: "0xTxHash1", LogIndex: 1, Status: model.TransactionStatusPending}
+
+	if got, err := s.GetTokenTransfers(context.Background(), address); err != nil || len(got) != 0 {
+		t.Errorf("GetTokenTransfers() = %v, %v, want empty", got, err)
+	}
+
+	for _, transfer := range []model.TokenTransfer{first, second} {
+		if err := s.AddTokenTransfer(context.Background(), address, transfer); err != nil {
+			t.Fatalf("AddTokenTransfer() error = %v", err)
+		}
+	}
+
+	if err := s.UpdateTokenTransferStatus(context.Background(), address, "0xTxHash1", 1, model.TransactionStatusConfirmed); err != nil {
+		t.Errorf("UpdateTokenTransferStatus() error = %v", err)
+	}
+	if err := s.RemoveTokenTransfer(context.Background(), address, "0xTxHash1", 0); err != nil {
+		t.Errorf("RemoveTokenTransfer() error = %v", err)
+	}
+
+	second.Status = model.TransactionStatusConfirmed
+
+	got, err := s.GetTokenTransfers(context.Background(), address)
+	if err != nil {
+		t.Fatalf("GetTokenTransfers() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, []model.TokenTransfer{second}) {
+		t.Errorf("GetTokenTransfers() = %v, want %v", got, []model.TokenTransfer{second})
+	}
+}
+
+func testQueryTransactions(t *testing.T, newStorage func(t *testing.T) Storage) {
+	s := newStorage(t)
+	ctx := context.Background()
+
+	address := model.Address("0xAddress1")
+	for i := uint64(0); i < 3; i++ {
+		tx := model.Transaction{Hash: fmt.Sprintf("0xTxHash%d", i), From: "0xAddress2", To: address, Value: big.NewInt(int64(i)), BlockNumber: 100 + i}
+		if err := s.AddTransaction(ctx, address, tx); err != nil {
+			t.Fatalf("AddTransaction() error = %v", err)
+		}
+	}
+
+	page, err := s.QueryTransactions(ctx, address, model.TransactionQuery{Order: model.SortDescending, Limit: 2, MinValue: big.NewInt(1)})
+	if err != nil {
+		t.Fatalf("QueryTransactions() error = %v", err)
+	}
+	if len(page.Transactions) != 2 || page.Transactions[0].Hash != "0xTxHash2" || page.Transactions[1].Hash != "0xTxHash1" || page.NextCursor != "" {
+		t.Errorf("QueryTransactions() = %+v, want 0xTxHash2 and 0xTxHash1 on a single page", page)
+	}
+
+	if _, err := s.QueryTransactions(ctx, address, model.TransactionQuery{Cursor: "bogus"}); !errors.Is(err, model.ErrInvalidCursor) {
+		t.Errorf("QueryTransactions() error = %v, want %v", err, model.ErrInvalidCursor)
+	}
+}
+
+func testGetTransactionsReturnsCopy(t *testing.T, newStorage func(t *testing.T) Storage) {
+	s := newStorage(t)
+	ctx := context.Background()
+
+	address := model.Address("0xAddress1")
+	_ = s.AddTransaction(ctx, address, model.Transaction{Hash: "0xTxHash1"})
+
+	txs, _ := s.GetTransactions(ctx, address)
+	txs[0].Hash = "0xModified"
+
+	if stored, _ := s.GetTransactions(ctx, address); stored[0].Hash != "0xTxHash1" {
+		t.Errorf("GetTransactions() exposed internal state: stored hash = %s", stored[0].Hash)
+	}
+}
+
+func testIdempotent(t *testing.T, newStorage func(t *testing.T) Storage) {
+	s := newStorage(t)
+	ctx := context.Background()
+
+	address := model.Address("0xAddress1")
+	tx := model.Transaction{Hash: "0xTxHash1", From: address, To: address, Status: model.TransactionStatusPending}
+	transfer := model.TokenTransfer{TransactionHash: "0xTxHash1", LogIndex: 2, Status: model.TransactionStatusPending}
+	internalTransfer := model.InternalTransfer{TransactionHash: "0xTxHash1", Index: 3, Status: model.TransactionStatusPending}
+
+	// Processing the same block twice stores everything once, the second
+	// write replacing the first.
+	for _, status := range []model.TransactionStatus{model.TransactionStatusPending, model.TransactionStatusConfirmed} {
+		tx.Status, transfer.Status, internalTransfer.Status = status, status, status
+
+		if err := s.AddTransaction(ctx, address, tx); err != nil {
+			t.Fatalf("AddTransaction() error = %v", err)
+		}
+		if err := s.AddTokenTransfer(ctx, address, transfer); err != nil {
+			t.Fatalf("AddTokenTransfer() error = %v", err)
+		}
+		if err := s.AddInternalTransfer(ctx, address, internalTransfer); err != nil {
+			t.Fatalf("AddInternalTransfer() error = %v", err)
+		}
+	}
+
+	if got, _ := s.GetTransactions(ctx, address); !reflect.DeepEqual(got, []model.Transaction{tx}) {
+		t.Errorf("GetTransactions() = %v, want %v", got, []model.Transaction{tx})
+	}
+	if got, _ := s.GetTokenTransfers(ctx, address); !reflect.DeepEqual(got, []model.TokenTransfer{transfer}) {
+		t.Errorf("GetTokenTransfers() = %v, want %v", got, []model.TokenTransfer{transfer})
+	}
+	if got, _ := s.GetInternalTransfers(ctx, address); !reflect.DeepEqual(got, []model.InternalTransfer{internalTransfer}) {
+		t.Errorf("GetInternalTransfers() = %v, want %v", got, []model.InternalTransfer{internalTransfer})
+	}
+}
+
+func testGetTransactionByHash(t *testing.T, newStorage func(t *testing.T) Storage) {
+	s := newStorage(t)
+	ctx := context.Background()
+
+	tx := model.Transaction{Hash: "0xTxHash1", From: "0xAddress1", To: "0xAddress2"}
+	_ = s.AddTransaction(ctx, tx.From, tx)
+	_ = s.AddTransaction(ctx, tx.To, tx)
+
+	if got, err := s.GetTransactionByHash(ctx, "0xTxHash1"); err != nil || got != tx {
+		t.Errorf("GetTransactionByHash() = %v, %v, want %v", got, err, tx)
+	}
+
+	// The transaction stays findable while any address stores it.
+	_ = s.RemoveTransaction(ctx, tx.From, tx.Hash)
+	if _, err := s.GetTransactionByHash(ctx, "0xTxHash1"); err != nil {
+		t.Errorf("GetTransactionByHash() error = %v after removing one holder", err)
+	}
+
+	_ = s.RemoveTransaction(ctx, tx.To, tx.Hash)
+	if _, err := s.GetTransactionByHash(ctx, "0xTxHash1"); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("GetTransactionByHash() error = %v, want %v", err, storage.ErrNotFound)
+	}
+}
+
+func testListAddresses(t *testing.T, newStorage func(t *testing.T) Storage) {
+	s := newStorage(t)
+
+	if got, err := s.ListAddresses(context.Background()); err != nil || len(got) != 0 {
+		t.Errorf("ListAddresses() = %v, %v, want empty", got, err)
+	}
+
+	for _, address := range []model.Address{"0xAddress2", "0xAddress1", "0xAddress2"} {
+		if err := s.AddAddress(context.Background(), address); err != nil {
+			t.Fatalf("AddAddress() error = %v", err)
+		}
+	}
+
+	want := []model.Address{"0xAddress1", "0xAddress2"}
+	got, err := s.ListAddresses(context.Background())
+	if err != nil {
+		t.Fatalf("ListAddresses() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ListAddresses() = %v, want %v", got, want)
+	}
+}
+
+func testSubscriptions(t *testing.T, newStorage func(t *testing.T) Storage) {
+	s := newStorage(t)
+	ctx := context.Background()
+
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	active := model.Subscription{Address: "0xAddress1", CreatedAt: createdAt, Label: "treasury"}
+	expiredAt := time.Now().Add(-time.Minute).UTC()
+	futureAt := time.Now().Add(time.Hour).UTC()
+	expired := model.Subscription{Address: "0xAddress2", CreatedAt: createdAt, ExpiresAt: &expiredAt}
+	future := model.Subscription{Address: "0xAddress3", CreatedAt: createdAt, ExpiresAt: &futureAt}
+
+	for _, subscription := range []model.Subscription{active, expired, future} {
+		if err := s.AddSubscription(ctx, subscription); err != nil {
+			t.Fatalf("AddSubscription() error = %v", err)
+		}
+	}
+
+	// Subscribing again updates the label but keeps the creation time.
+	relabeled := active
+	relabeled.CreatedAt = time.Now()
+	relabeled.Label = "cold wallet"
+	if err := s.AddSubscription(ctx, relabeled); err != nil {
+		t.Fatalf("AddSubscription() error = %v", err)
+	}
+	active.Label = "cold wallet"
+
+	for address, want := range map[model.Address]bool{"0xAddress1": true, "0xAddress2": false, "0xAddress3": true} {
+		if got, err := s.IsSubscribed(ctx, address); err != nil || got != want {
+			t.Errorf("IsSubscribed(%s) = %v, %v, want %v", address, got, err, want)
+		}
+	}
+
+	if got, err := s.ListAddresses(ctx); err != nil || !reflect.DeepEqual(got, []model.Address{"0xAddress1", "0xAddress3"}) {
+		t.Errorf("ListAddresses() = %v, %v, want active addresses", got, err)
+	}
+
+	firstPage, err := s.ListSubscriptions(ctx, "", 2)
+	if err != nil {
+		t.Fatalf("ListSubscriptions() error = %v", err)
+	}
+	if !reflect.DeepEqual(firstPage, []model.Subscription{active, expired}) {
+		t.Errorf("ListSubscriptions() first page = %v, want %v", firstPage, []model.Subscription{active, expired})
+	}
+
+	secondPage, err := s.ListSubscriptions(ctx, firstPage[len(firstPage)-1].Address, 2)
+	if err != nil {
+		t.Fatalf("ListSubscriptions() error = %v", err)
+	}
+	if !reflect.DeepEqual(secondPage, []model.Subscription{future}) {
+		t.Errorf("ListSubscriptions() second page = %v, want %v", secondPage, []model.Subscription{future})
+	}
+
+	if all, err := s.ListSubscriptions(ctx, "", 0); err != nil || len(all) != 3 {
+		t.Errorf("ListSubscriptions() without limit = %v, %v, want 3 subscriptions", all, err)
+	}
+}
+
+func testRemoveSubscription(t *testing.T, newStorage func(t *testing.T) Storage) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		purge   bool
+		wantTxs int
+	}{
+		{"keep activity", false, 1},
+		{"purge activity", true, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newStorage(t)
+			address := model.Address("0xAddress1")
+
+			_ = s.AddAddress(ctx, address)
+			_ = s.AddTransaction(ctx, address, model.Transaction{Hash: "0xTxHash1"})
+			_ = s.AddTokenTransfer(ctx, address, model.TokenTransfer{TransactionHash: "0xTxHash1"})
+			_ = s.AddInternalTransfer(ctx, address, model.InternalTransfer{TransactionHash: "0xTxHash1"})
+
+			if err := s.RemoveSubscription(ctx, address, tt.purge); err != nil {
+				t.Fatalf("RemoveSubscription() error = %v", err)
+			}
+
+			if subscribed, _ := s.IsSubscribed(ctx, address); subscribed {
+				t.Errorf("IsSubscribed() = true after RemoveSubscription()")
+			}
+
+			txs, _ := s.GetTransactions(ctx, address)
+			transfers, _ := s.GetTokenTransfers(ctx, address)
+			internalTransfers, _ := s.GetInternalTransfers(ctx, address)
+			if len(txs) != tt.wantTxs || len(transfers) != tt.wantTxs || len(internalTransfers) != tt.wantTxs {
+				t.Errorf("stored activity = %d, %d, %d, want %d of each", len(txs), len(transfers), len(internalTransfers), tt.wantTxs)
+			}
+
+			if err := s.RemoveSubscription(ctx, address, tt.purge); !errors.Is(err, storage.ErrNotSubscribed) {
+				t.Errorf("RemoveSubscription() again error = %v, want %v", err, storage.ErrNotSubscribed)
+			}
+		})
+	}
+}
+
+func testInternalTransfers(t *testing.T, newStorage func(t *testing.T) Storage) {
+	s := newStorage(t)
+
+	address := model.Address("0xAddress1")
+	first := model.InternalTransfer{TransactionHash: "0xTxHash1", Index: 1, Type: "call", From: "0xContract", To: address, Value: big.NewInt(100), Status: model.TransactionStatusPending}
+	second := model.InternalTransfer{TransactionHash: "0xTxHash1", Index: 2, Type: "call", From: "0xContract", To: address, Value: big.NewInt(200), Status: model.TransactionStatusPending}
+
+	if got, err := s.GetInternalTransfers(context.Background(), address); err != nil || len(got) != 0 {
+		t.Errorf("GetInternalTransfers() = %v, %v, want empty", got, err)
+	}
+
+	for _, transfer := range []model.InternalTransfer{first, second} {
+		if err := s.AddInternalTransfer(context.Background(), address, transfer); err != nil {
+			t.Fatalf("AddInternalTransfer() error = %v", err)
+		}
+	}
+
+	if err := s.UpdateInternalTransferStatus(context.Background(), address, "0xTxHash1", 2, model.TransactionStatusConfirmed); err != nil {
+		t.Errorf("UpdateInternalTransferStatus() error = %v", err)
+	}
+	if err := s.RemoveInternalTransfer(context.Background(), address, "0xTxHash1", 1); err != nil {
+		t.Errorf("RemoveInternalTransfer() error = %v", err)
+	}
+
+	second.Status = model.TransactionStatusConfirmed
+
+	got, err := s.GetInternalTransfers(context.Background(), address)
+	if err != nil {
+		t.Fatalf("GetInternalTransfers() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, []model.InternalTransfer{second}) {
+		t.Errorf("GetInternalTransfers() = %v, want %v", got, []model.InternalTransfer{second})
+	}
+}
+
+func testCommitBatch(t *testing.T, newStorage func(t *testing.T) Storage) {
+	address := model.Address("0xAddress1")
+	tx := model.Transaction{Hash: "0xTxHash1", From: address, To: "0xAddress2"}
+	transfer := model.TokenTransfer{TransactionHash: "0xTxHash1", LogIndex: 1, From: address}
+	internalTransfer := model.InternalTransfer{TransactionHash: "0xTxHash1", Index: 1, To: address}
+	checkpoint := model.Checkpoint{BlockNumber: 100, BlockHash: "0xBlock100"}
+
+	var batch storage.Batch
+	batch.AddTransaction(address, tx)
+	batch.AddTokenTransfer(address, transfer)
+	batch.AddInternalTransfer(address, internalTransfer)
+	batch.SaveCheckpoint(checkpoint)
+
+	t.Run("commits everything", func(t *testing.T) {
+		s := newStorage(t)
+		ctx := context.Background()
+
+		if err := s.CommitBatch(ctx, batch); err != nil {
+			t.Fatalf("CommitBatch() error = %v", err)
+		}
+
+		if got, _ := s.GetTransactions(ctx, address); !reflect.DeepEqual(got, []model.Transaction{tx}) {
+			t.Errorf("GetTransactions() = %v, want %v", got, []model.Transaction{tx})
+		}
+		if got, _ := s.GetTokenTransfers(ctx, address); !reflect.DeepEqual(got, []model.TokenTransfer{transfer}) {
+			t.Errorf("GetTokenTransfers() = %v, want %v", got, []model.TokenTransfer{transfer})
+		}
+		if got, _ := s.GetInternalTransfers(ctx, address); !reflect.DeepEqual(got, []model.InternalTransfer{internalTransfer}) {
+			t.Errorf("GetInternalTransfers() = %v, want %v", got, []model.InternalTransfer{internalTransfer})
+		}
+		if got, err := s.LoadCheckpoint(ctx); err != nil || !reflect.DeepEqual(got, checkpoint) {
+			t.Errorf("LoadCheckpoint() = %v, %v, want %v", got, err, checkpoint)
+		}
+	})
+
+	t.Run("removes and updates", func(t *testing.T) {
+		s := newStorage(t)
+		ctx := context.Background()
+
+		orphaned := model.Transaction{Hash: "0xTxHash2", From: address, To: "0xAddress2"}
+		initial := batch
+		initial.AddTransaction(address, orphaned)
+		if err := s.CommitBatch(ctx, initial); err != nil {
+			t.Fatalf("CommitBatch() error = %v", err)
+		}
+
+		rewound := model.Checkpoint{BlockNumber: 99, BlockHash: "0xBlock99"}
+
+		var rollback storage.Batch
+		rollback.RemoveTransaction(address, orphaned.Hash)
+		rollback.RemoveTokenTransfer(address, transfer.TransactionHash, transfer.LogIndex)
+		rollback.UpdateTransactionStatus(address, tx.Hash, model.TransactionStatusConfirmed)
+		rollback.UpdateInternalTransferStatus(address, internalTransfer.TransactionHash, internalTransfer.Index, model.TransactionStatusConfirmed)
+		rollback.SaveCheckpoint(rewound)
+
+		if err := s.CommitBatch(ctx, rollback); err != nil {
+			t.Fatalf("CommitBatch() error = %v", err)
+		}
+
+		confirmedTx := tx
+		confirmedTx.Status = model.TransactionStatusConfirmed
+		if got, _ := s.GetTransactions(ctx, address); !reflect.DeepEqual(got, []model.Transaction{confirmedTx}) {
+			t.Errorf("GetTransactions() = %v, want %v", got, []model.Transaction{confirmedTx})
+		}
+		if _, err := s.GetTransactionByHash(ctx, orphaned.Hash); !errors.Is(err, storage.ErrNotFound) {
+			t.Errorf("GetTransactionByHash() error = %v, want %v", err, storage.ErrNotFound)
+		}
+		if got, _ := s.GetTokenTransfers(ctx, address); len(got) != 0 {
+			t.Errorf("GetTokenTransfers() = %v, want empty", got)
+		}
+
+		confirmedInternal := internalTransfer
+		confirmedInternal.Status = model.TransactionStatusConfirmed
+		if got, _ := s.GetInternalTransfers(ctx, address); !reflect.DeepEqual(got, []model.InternalTransfer{confirmedInternal}) {
+			t.Errorf("GetInternalTransfers() = %v, want %v", got, []model.InternalTransfer{confirmedInternal})
+		}
+		if got, err := s.LoadCheckpoint(ctx); err != nil || !reflect.DeepEqual(got, rewound) {
+			t.Errorf("LoadCheckpoint() = %v, %v, want %v", got, err, rewound)
+		}
+	})
+
+	t.Run("commits nothing when cancelled", func(t *testing.T) {
+		s := newStorage(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		if err := s.CommitBatch(ctx, batch); !errors.Is(err, context.Canceled) {
+			t.Fatalf("CommitBatch() error = %v, want %v", err, context.Canceled)
+		}
+
+		if got, _ := s.GetTransactions(context.Background(), address); len(got) != 0 {
+			t.Errorf("GetTransactions() = %v, want empty", got)
+		}
+		if _, err := s.LoadCheckpoint(context.Background()); !errors.Is(err, storage.ErrNoCheckpoint) {
+			t.Errorf("LoadCheckpoint() error = %v, want %v", err, storage.ErrNoCheckpoint)
+		}
+	})
+}
+
+func testCheckpoint(t *testing.T, newStorage func(t *testing.T) Storage) {
+	s := newStorage(t)
+
+	if _, err := s.LoadCheckpoint(context.Background()); !errors.Is(err, storage.ErrNoCheckpoint) {
+		t.Errorf("LoadCheckpoint() error = %v, want %v", err, storage.ErrNoCheckpoint)
+	}
+
+	want := model.Checkpoint{BlockNumber: 100, BlockHash: "0xBlock100"}
+	if err := s.SaveCheckpoint(context.Background(), want); err != nil {
+		t.Fatalf("SaveCheckpoint() error = %v", err)
+	}
+
+	got, err := s.LoadCheckpoint(context.Background())
+	if err != nil {
+		t.Fatalf("LoadCheckpoint() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("LoadCheckpoint() = %v, want %v", got, want)
+	}
+}
+
+func testConcurrency(t *testing.T, newStorage func(t *testing.T) Storage) {
+	s := newStorage(t)
+
+	address := model.Address("0xAddress1")
+	tx := model.Transaction{
+		Hash:        "0xTxHash1",
+		From:        address,
+		To:          "0xAddress2",
+		Value:       big.NewInt(100),
+		BlockNumber: 1,
+	}
+
+	// Simulate concurrent access
+	done := make(chan bool)
+	go func() {
+		for i := 0; i < 1000; i++ {
+			tx.Hash = fmt.Sprintf("0xTxHash%d", i)
+			_ = s.AddAddress(context.Background(), address)
+			_ = s.AddTransaction(context.Background(), address, tx)
+		}
+		done <- true
+	}()
+
+	go func() {
+		for i := 0; i < 1000; i++ {
+			_, _ = s.IsSubscribed(context.Background(), address)
+			_, _ = s.GetTransactions(context.Background(), address)
+		}
+		done <- true
+	}()
+
+	<-done
+	<-done
+
+	// Verify final state
+	subscribed, err := s.IsSubscribed(context.Background(), address)
+	if err != nil {
+		t.Errorf("IsSubscribed() error = %v", err)
+	}
+	if !subscribed {
+		t.Errorf("Expected address to be subscribed")
+	}
+
+	txs, err := s.GetTransactions(context.Background(), address)
+	if err != nil {
+		t.Errorf("GetTransactions() error = %v", err)
+	}
+	if len(txs) != 1000 {
+		t.Errorf("Expected 1000 transactions, got %d", len(txs))
+	}
+}